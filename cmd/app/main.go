@@ -1,25 +1,33 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/lib/pq"
 
 	"PR_project/internal/api"
+	"PR_project/internal/config"
 	"PR_project/internal/repository"
 	"PR_project/internal/service"
 )
 
 func main() {
-	dsn := os.Getenv("DATABASE_URL")
-	if dsn == "" {
-		log.Fatal("DATABASE_URL is not set")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
 		log.Fatal("failed to connect to DB:", err)
 	}
@@ -31,25 +39,81 @@ func main() {
 	teamRepo := repository.NewPostgresTeamRepository(db)
 	userRepo := repository.NewPostgresUserRepository(db)
 	prRepo := repository.NewPostgresPrRepository(db)
+	auditRepo := repository.NewPostgresAuditRepository(db)
+	slaRepo := repository.NewPostgresSLARepository(db)
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	outboxRepo := repository.NewPostgresOutboxRepository(db)
 
-	teamService := &service.TeamService{TRepository: teamRepo}
-	userService := &service.UserService{URepository: userRepo}
+	selector, err := service.NewTeamSelector(cfg.ReviewerStrategy, cfg.TeamReviewerStrategies, userRepo)
+	if err != nil {
+		log.Fatal("invalid reviewer strategy config:", err)
+	}
+
+	auditService := &service.AuditService{ARepository: auditRepo}
+	webhookService := &service.WebhookService{WRepository: webhookRepo}
+	userService := &service.UserService{
+		URepository: userRepo,
+		TRepository: teamRepo,
+	}
+	teamService := &service.TeamService{
+		TRepository: teamRepo,
+		URepository: userRepo,
+		UserService: userService,
+	}
 	prService := &service.PrService{
-		PrRepository: prRepo,
-		URepository:  userRepo,
+		PrRepository:   prRepo,
+		URepository:    userRepo,
+		TRepository:    teamRepo,
+		Selector:       selector,
+		OverrideActors: cfg.MergeOverrideActors,
 	}
 
 	handler := api.Handler{
-		TeamService: teamService,
-		UserService: userService,
-		PrService:   prService,
+		TeamService:    teamService,
+		UserService:    userService,
+		PrService:      prService,
+		AuditService:   auditService,
+		WebhookService: webhookService,
+	}
+
+	if cfg.SLAScanInterval > 0 {
+		slaWorker := &service.SLAWorker{
+			Repository: slaRepo,
+			PrService:  prService,
+			Interval:   cfg.SLAScanInterval,
+		}
+		go slaWorker.Run(ctx)
+	}
+
+	if cfg.OutboxPollInterval > 0 {
+		outbox := &service.OutboxDispatcher{
+			Repository: outboxRepo,
+			Publisher:  webhookService,
+			Interval:   cfg.OutboxPollInterval,
+		}
+		go outbox.Run(ctx)
+	}
+
+	if cfg.WebhookDispatchInterval > 0 {
+		dispatcher := &service.WebhookDispatcher{
+			Repository: webhookRepo,
+			Client:     &http.Client{},
+			Interval:   cfg.WebhookDispatchInterval,
+		}
+		go dispatcher.Run(ctx)
 	}
 
 	mux := http.NewServeMux()
 	handler.RegisterRoutes(mux)
 
+	server := &http.Server{Addr: ":8080", Handler: api.WithActor(mux)}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
 	log.Println("Server started on :8080")
-	if err := http.ListenAndServe(":8080", mux); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,68 @@
+// Command webhook-echo is a local stand-in for a webhook subscriber. It
+// logs every delivery it receives and checks its signature when
+// WEBHOOK_SECRET is set. WEBHOOK_ECHO_FAIL_FIRST makes it answer 503 to
+// that many requests first, to exercise retries.
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"PR_project/internal/service"
+)
+
+func main() {
+	addr := os.Getenv("WEBHOOK_ECHO_ADDR")
+	if addr == "" {
+		addr = ":9090"
+	}
+	secret := os.Getenv("WEBHOOK_SECRET")
+
+	var failFirst int64
+	if raw := os.Getenv("WEBHOOK_ECHO_FAIL_FIRST"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			log.Fatalf("WEBHOOK_ECHO_FAIL_FIRST: invalid count %q", raw)
+		}
+		failFirst = n
+	}
+
+	var received atomic.Int64
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		event := r.Header.Get("X-Webhook-Event")
+		delivery := r.Header.Get("X-Webhook-Delivery")
+		n := received.Add(1)
+
+		if secret != "" && !service.VerifySignature(secret, body, r.Header.Get(service.SignatureHeader)) {
+			log.Printf("#%d delivery %s (%s): bad signature", n, delivery, event)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if n <= failFirst {
+			log.Printf("#%d delivery %s (%s): failing on purpose", n, delivery, event)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		log.Printf("#%d delivery %s (%s): %s", n, delivery, event, body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Println("Webhook echo listening on", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
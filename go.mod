@@ -2,4 +2,4 @@ module PR_project
 
 go 1.25
 
-require github.com/lib/pq v1.10.9 // indirect
+require github.com/lib/pq v1.10.9
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"PR_project/internal/service"
+	"net/http"
+)
+
+const actorHeader = "X-Actor-ID"
+
+// WithActor passes the caller identity from the X-Actor-ID header
+// down to the services, which record it in the history.
+func WithActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(actorHeader)
+		if actor != "" {
+			r = r.WithContext(service.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
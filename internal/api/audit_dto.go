@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntryDTO struct {
+	AuditID    int64           `json:"audit_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+type AuditListResponse struct {
+	Entries []AuditEntryDTO `json:"entries"`
+	// NextCursor is passed as cursor to fetch the next page, null on the last one.
+	NextCursor *string `json:"next_cursor"`
+}
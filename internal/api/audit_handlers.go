@@ -0,0 +1,144 @@
+package api
+
+import (
+	"PR_project/internal/domain"
+	"PR_project/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (h *Handler) handleAuditList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	entries, next, err := h.AuditService.List(ctx, filter)
+	if errors.Is(err, service.ErrInvalidAuditFilter) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "limit must be between 1 and 500")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	entriesDTO := make([]AuditEntryDTO, 0, len(entries))
+	for _, e := range entries {
+		entriesDTO = append(entriesDTO, toAuditEntryDTO(e))
+	}
+
+	resp := AuditListResponse{
+		Entries: entriesDTO,
+	}
+	if next != 0 {
+		cursor := strconv.FormatInt(next, 10)
+		resp.NextCursor = &cursor
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleAuditExport streams every matching entry as JSON Lines.
+func (h *Handler) handleAuditExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+	w.WriteHeader(http.StatusOK)
+
+	// Headers are already sent, so a failure part way through can only
+	// cut the stream short.
+	enc := json.NewEncoder(w)
+	_ = h.AuditService.Export(r.Context(), filter, func(e domain.AuditEntry) error {
+		return enc.Encode(toAuditEntryDTO(e))
+	})
+}
+
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor:      q.Get("actor"),
+		Action:     q.Get("action"),
+		EntityType: q.Get("entity_type"),
+		EntityID:   q.Get("entity_id"),
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.AuditFilter{}, fmt.Errorf("%s must be an RFC 3339 timestamp", p.name)
+		}
+		*p.dst = &t
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return domain.AuditFilter{}, errors.New("from must be before to")
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor <= 0 {
+			return domain.AuditFilter{}, errors.New("cursor is invalid")
+		}
+		filter.BeforeID = cursor
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return domain.AuditFilter{}, errors.New("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func toAuditEntryDTO(e domain.AuditEntry) AuditEntryDTO {
+	return AuditEntryDTO{
+		AuditID:    e.ID,
+		OccurredAt: e.OccurredAt,
+		Actor:      e.Actor,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     rawOrNull(e.Before),
+		After:      rawOrNull(e.After),
+	}
+}
+
+func rawOrNull(payload json.RawMessage) json.RawMessage {
+	if len(payload) == 0 {
+		return json.RawMessage("null")
+	}
+	return payload
+}
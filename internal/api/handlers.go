@@ -3,19 +3,25 @@ package api
 import "PR_project/internal/service"
 
 type Handler struct {
-	TeamService *service.TeamService
-	UserService *service.UserService
-	PrService   *service.PrService
+	TeamService    *service.TeamService
+	UserService    *service.UserService
+	PrService      *service.PrService
+	AuditService   *service.AuditService
+	WebhookService *service.WebhookService
 }
 
 func NewHandler(
 	teamService *service.TeamService,
 	userService *service.UserService,
 	prService *service.PrService,
+	auditService *service.AuditService,
+	webhookService *service.WebhookService,
 ) *Handler {
 	return &Handler{
-		TeamService: teamService,
-		UserService: userService,
-		PrService:   prService,
+		TeamService:    teamService,
+		UserService:    userService,
+		PrService:      prService,
+		AuditService:   auditService,
+		WebhookService: webhookService,
 	}
 }
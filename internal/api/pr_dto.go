@@ -3,9 +3,13 @@ package api
 import "time"
 
 type PrReqDTO struct {
-	PrID     string `json:"pull_request_id"`
-	Name     string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
+	PrID         string   `json:"pull_request_id"`
+	Name         string   `json:"pull_request_name"`
+	AuthorID     string   `json:"author_id"`
+	ChangedFiles []string `json:"changed_files"`
+	Labels       []string `json:"labels"`
+	Draft        bool     `json:"draft"`
+	Seed         *int64   `json:"seed"`
 }
 
 type PrReassignReqDTO struct {
@@ -13,14 +17,86 @@ type PrReassignReqDTO struct {
 	OldReviewer string `json:"old_reviewer_id"`
 }
 
+type PrMergeReqDTO struct {
+	PrID string `json:"pull_request_id"`
+	// AdminOverride merges regardless of the team's approval policy.
+	AdminOverride bool `json:"admin_override"`
+}
+
+type PrStatusReqDTO struct {
+	PrID   string `json:"pull_request_id"`
+	Reason string `json:"reason"`
+}
+
+type PrReviewReqDTO struct {
+	PrID       string `json:"pull_request_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Verdict    string `json:"verdict"`
+	Comment    string `json:"comment"`
+}
+
+type ReviewDTO struct {
+	ReviewerID  string    `json:"reviewer_id"`
+	Verdict     string    `json:"verdict"`
+	Comment     string    `json:"comment"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
 type PrDTO struct {
+	PrID         string      `json:"pull_request_id"`
+	Name         string      `json:"pull_request_name"`
+	AuthorID     string      `json:"author_id"`
+	Status       string      `json:"status"`
+	ReviewersIDs []string    `json:"assigned_reviewers"`
+	Labels       []string    `json:"labels"`
+	Reviews      []ReviewDTO `json:"reviews"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	MergedAt     *time.Time  `json:"mergedAt"`
+	ClosedAt     *time.Time  `json:"closedAt"`
+}
+
+type PrUserDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type PrReviewerDetailsDTO struct {
+	PrUserDTO
+	AssignedAt *time.Time `json:"assigned_at"`
+	// Review is null until the reviewer submits a verdict.
+	Review *ReviewDTO `json:"review"`
+}
+
+// PrDetailsDTO is PrDTO with the author and reviewers resolved to users.
+type PrDetailsDTO struct {
+	PrDTO
+	Author    PrUserDTO              `json:"author"`
+	Reviewers []PrReviewerDetailsDTO `json:"reviewers"`
+}
+
+type PrGetResponse struct {
+	Pr PrDetailsDTO `json:"pr"`
+}
+
+// PrListItemDTO is a pull request as listed, without reviews.
+type PrListItemDTO struct {
 	PrID         string     `json:"pull_request_id"`
 	Name         string     `json:"pull_request_name"`
 	AuthorID     string     `json:"author_id"`
 	Status       string     `json:"status"`
 	ReviewersIDs []string   `json:"assigned_reviewers"`
+	Labels       []string   `json:"labels"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	MergedAt     *time.Time `json:"mergedAt"`
+	ClosedAt     *time.Time `json:"closedAt"`
+}
+
+type PrListResponse struct {
+	PullRequests []PrListItemDTO `json:"pull_requests"`
+	// NextCursor is passed as cursor to fetch the next page, null on the last one.
+	NextCursor *string `json:"next_cursor"`
 }
 
 type PrShortDTO struct {
@@ -34,7 +110,84 @@ type PrAddResponse struct {
 	Pr PrDTO `json:"pr"`
 }
 
+type AssignmentDTO struct {
+	MinReviewers      int      `json:"min_reviewers"`
+	MaxReviewers      int      `json:"max_reviewers"`
+	Assigned          int      `json:"assigned"`
+	Understaffed      bool     `json:"understaffed"`
+	FallbackReviewers []string `json:"fallback_reviewers"`
+	OwnerReviewers    []string `json:"code_owner_reviewers"`
+	TagReviewers      []string `json:"tag_match_reviewers"`
+	OverCapacity      []string `json:"over_capacity_reviewers"`
+}
+
+type PrCreateResponse struct {
+	Pr PrDTO `json:"pr"`
+	// Assignment is omitted for drafts.
+	Assignment *AssignmentDTO `json:"assignment,omitempty"`
+}
+
 type PrReassignedResponse struct {
-	Pr         PrDTO  `json:"pr"`
-	ReplacedBy string `json:"replaced_by"`
+	Pr                 PrDTO  `json:"pr"`
+	ReplacedBy         string `json:"replaced_by"`
+	ReplacedByFallback bool   `json:"replaced_by_fallback"`
+}
+
+type ReviewerRationaleDTO struct {
+	UserID         string     `json:"user_id"`
+	Strategy       string     `json:"strategy"`
+	Reason         string     `json:"reason"`
+	FallbackTeam   string     `json:"fallback_team,omitempty"`
+	CandidatePool  []string   `json:"candidate_pool"`
+	ReplacedUserID string     `json:"replaced_user_id,omitempty"`
+	AssignedAt     *time.Time `json:"assigned_at"`
+}
+
+type PrAssignmentExplainResponse struct {
+	PrID      string                 `json:"pull_request_id"`
+	Reviewers []ReviewerRationaleDTO `json:"reviewers"`
+}
+
+type ProposedReviewerDTO struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	TeamName     string `json:"team_name"`
+	Strategy     string `json:"strategy"`
+	Reason       string `json:"reason"`
+	FallbackTeam string `json:"fallback_team,omitempty"`
+}
+
+type PrPreviewResponse struct {
+	AuthorID          string                `json:"author_id"`
+	CandidatePool     []string              `json:"candidate_pool"`
+	ProposedReviewers []ProposedReviewerDTO `json:"proposed_reviewers"`
+	Assignment        AssignmentDTO         `json:"assignment"`
+	Seed              int64                 `json:"seed"`
+}
+
+type PrTransitionDTO struct {
+	FromStatus *string   `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor,omitempty"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type PrHistoryResponse struct {
+	PrID    string            `json:"pull_request_id"`
+	History []PrTransitionDTO `json:"history"`
+}
+
+type EscalationDTO struct {
+	ReviewerID   string    `json:"reviewer_id"`
+	Action       string    `json:"action"`
+	ReplacedBy   string    `json:"replaced_by,omitempty"`
+	Note         string    `json:"note,omitempty"`
+	SLAStartedAt time.Time `json:"sla_started_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PrEscalationsResponse struct {
+	PrID        string          `json:"pull_request_id"`
+	Escalations []EscalationDTO `json:"escalations"`
 }
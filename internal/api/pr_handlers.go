@@ -1,10 +1,16 @@
 package api
 
 import (
+	"PR_project/internal/domain"
 	"PR_project/internal/service"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -35,7 +41,15 @@ func (h *Handler) handlePrAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	pr, err := h.PrService.CreatePRWithReviewers(ctx, req.PrID, req.Name, req.AuthorID)
+	pr, assignment, err := h.PrService.CreatePRWithReviewers(ctx, service.CreatePrInput{
+		ID:           req.PrID,
+		Name:         req.Name,
+		AuthorID:     req.AuthorID,
+		ChangedFiles: req.ChangedFiles,
+		Labels:       req.Labels,
+		Draft:        req.Draft,
+		Seed:         req.Seed,
+	})
 	if errors.Is(err, service.ErrPrAlreadyExists) {
 		writeError(w, http.StatusConflict, "PR_EXISTS", "PR id already exists")
 		return
@@ -44,24 +58,24 @@ func (h *Handler) handlePrAdd(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
 		return
 	}
+	if errors.Is(err, service.ErrReviewersAtCapacity) {
+		writeError(w, http.StatusConflict, "REVIEWERS_AT_CAPACITY", "all candidate reviewers are at capacity")
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
 		return
 	}
 
-	respPr := PrDTO{
-		PrID:         pr.ID,
-		Name:         pr.Name,
-		AuthorID:     pr.AuthorID,
-		Status:       pr.Status,
-		CreatedAt:    pr.CreatedAt,
-		MergedAt:     pr.MergedAt,
-		ReviewersIDs: pr.ReviewersIDs,
-	}
+	respPr := toPrDTO(pr)
 
-	resp := PrAddResponse{
+	resp := PrCreateResponse{
 		Pr: respPr,
 	}
+	if pr.Status != domain.PrStatusDraft {
+		assignmentDTO := toAssignmentDTO(assignment)
+		resp.Assignment = &assignmentDTO
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(resp)
@@ -73,6 +87,65 @@ func (h *Handler) handleMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req PrMergeReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.PrID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	pr, err := h.PrService.Merge(ctx, req.PrID, time.Now(), req.AdminOverride)
+	if errors.Is(err, service.ErrOverrideNotAllowed) {
+		writeError(w, http.StatusForbidden, "OVERRIDE_NOT_ALLOWED", "actor is not allowed to use admin_override")
+		return
+	}
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if errors.Is(err, service.ErrPrClosed) {
+		writeError(w, http.StatusConflict, "PR_CLOSED", "cannot merge closed PR, reopen it first")
+		return
+	}
+	if errors.Is(err, service.ErrPrDraft) {
+		writeError(w, http.StatusConflict, "PR_DRAFT", "cannot merge draft PR, mark it ready first")
+		return
+	}
+	if errors.Is(err, service.ErrApprovalRequired) {
+		writeError(w, http.StatusConflict, "APPROVAL_REQUIRED", "PR does not satisfy the team approval policy")
+		return
+	}
+	if errors.Is(err, service.ErrPrStatusConflict) {
+		writeError(w, http.StatusConflict, "PR_STATUS_CONFLICT", "PR status changed concurrently, retry the request")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	respPr := toPrDTO(pr)
+
+	resp := PrAddResponse{
+		Pr: respPr,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handlePrReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
 	var req PrReqDTO
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
@@ -86,28 +159,161 @@ func (h *Handler) handleMerge(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	pr, err := h.PrService.Merge(ctx, req.PrID, time.Now())
+	pr, assignment, err := h.PrService.Ready(ctx, req.PrID)
 	if errors.Is(err, service.ErrPrNotFound) {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
 		return
 	}
+	if errors.Is(err, service.ErrPrNotDraft) {
+		writeError(w, http.StatusConflict, "PR_NOT_DRAFT", "PR is not a draft")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if errors.Is(err, service.ErrReviewersAtCapacity) {
+		writeError(w, http.StatusConflict, "REVIEWERS_AT_CAPACITY", "all candidate reviewers are at capacity")
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
 		return
 	}
 
-	respPr := PrDTO{
-		PrID:         pr.ID,
-		Name:         pr.Name,
-		AuthorID:     pr.AuthorID,
-		Status:       pr.Status,
-		CreatedAt:    pr.CreatedAt,
-		MergedAt:     pr.MergedAt,
-		ReviewersIDs: pr.ReviewersIDs,
+	assignmentDTO := toAssignmentDTO(assignment)
+	resp := PrCreateResponse{
+		Pr:         toPrDTO(pr),
+		Assignment: &assignmentDTO,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handlePrClose(w http.ResponseWriter, r *http.Request) {
+	h.handlePrStatusChange(w, r, func(ctx context.Context, prID, reason string) (domain.PullRequest, error) {
+		return h.PrService.Close(ctx, prID, reason, time.Now())
+	})
+}
+
+func (h *Handler) handlePrReopen(w http.ResponseWriter, r *http.Request) {
+	h.handlePrStatusChange(w, r, h.PrService.Reopen)
+}
+
+func (h *Handler) handlePrStatusChange(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, prID, reason string) (domain.PullRequest, error),
+) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req PrStatusReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.PrID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	pr, err := change(ctx, req.PrID, req.Reason)
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if errors.Is(err, service.ErrPrAlreadyMerged) {
+		writeError(w, http.StatusConflict, "PR_MERGED", "merged PR cannot change status")
+		return
+	}
+	if errors.Is(err, service.ErrPrDraft) {
+		writeError(w, http.StatusConflict, "PR_DRAFT", "draft PR must be marked ready first")
+		return
+	}
+	if errors.Is(err, service.ErrPrStatusConflict) {
+		writeError(w, http.StatusConflict, "PR_STATUS_CONFLICT", "PR status changed concurrently, retry the request")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
 	}
 
 	resp := PrAddResponse{
-		Pr: respPr,
+		Pr: toPrDTO(pr),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handlePrReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req PrReviewReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.PrID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+	if req.ReviewerID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "reviewer_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	pr, err := h.PrService.SubmitReview(ctx, domain.Review{
+		PullRequestID: req.PrID,
+		ReviewerID:    req.ReviewerID,
+		Verdict:       req.Verdict,
+		Comment:       req.Comment,
+	})
+	if errors.Is(err, service.ErrInvalidVerdict) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "verdict must be APPROVED or CHANGES_REQUESTED")
+		return
+	}
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if errors.Is(err, service.ErrPrAlreadyMerged) {
+		writeError(w, http.StatusConflict, "PR_MERGED", "cannot review merged PR")
+		return
+	}
+	if errors.Is(err, service.ErrPrClosed) {
+		writeError(w, http.StatusConflict, "PR_CLOSED", "cannot review closed PR")
+		return
+	}
+	if errors.Is(err, service.ErrPrDraft) {
+		writeError(w, http.StatusConflict, "PR_DRAFT", "cannot review draft PR")
+		return
+	}
+	if errors.Is(err, service.ErrUserIsNotReviewer) {
+		writeError(w, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := PrAddResponse{
+		Pr: toPrDTO(pr),
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -146,12 +352,24 @@ func (h *Handler) handleReassign(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
 		return
 	}
+	if errors.Is(err, service.ErrPrClosed) {
+		writeError(w, http.StatusConflict, "PR_CLOSED", "cannot reassign on closed PR")
+		return
+	}
+	if errors.Is(err, service.ErrPrDraft) {
+		writeError(w, http.StatusConflict, "PR_DRAFT", "cannot reassign on draft PR")
+		return
+	}
 	if errors.Is(err, service.ErrUserIsNotReviewer) {
 		writeError(w, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
 		return
 	}
 	if errors.Is(err, service.ErrNoCandidate) {
-		writeError(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
+		writeError(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team or fallback teams")
+		return
+	}
+	if errors.Is(err, service.ErrReviewersAtCapacity) {
+		writeError(w, http.StatusConflict, "REVIEWERS_AT_CAPACITY", "all replacement candidates are at capacity")
 		return
 	}
 	if err != nil {
@@ -159,21 +377,462 @@ func (h *Handler) handleReassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respPr := PrDTO{
+	respPr := toPrDTO(pr)
+
+	resp := PrReassignedResponse{
+		Pr:                 respPr,
+		ReplacedBy:         replacedBy.User.ID,
+		ReplacedByFallback: replacedBy.FromFallback(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toPrDTO(pr domain.PullRequest) PrDTO {
+	reviews := make([]ReviewDTO, 0, len(pr.Reviews))
+	for _, rv := range pr.Reviews {
+		reviews = append(reviews, toReviewDTO(rv))
+	}
+
+	return PrDTO{
 		PrID:         pr.ID,
 		Name:         pr.Name,
 		AuthorID:     pr.AuthorID,
-		Status:       pr.Status,
+		Status:       string(pr.Status),
 		CreatedAt:    pr.CreatedAt,
 		MergedAt:     pr.MergedAt,
+		ClosedAt:     pr.ClosedAt,
 		ReviewersIDs: pr.ReviewersIDs,
+		Labels:       append([]string{}, pr.Labels...),
+		Reviews:      reviews,
 	}
+}
 
-	resp := PrReassignedResponse{
-		Pr:         respPr,
-		ReplacedBy: replacedBy,
+func toReviewDTO(rv domain.Review) ReviewDTO {
+	return ReviewDTO{
+		ReviewerID:  rv.ReviewerID,
+		Verdict:     rv.Verdict,
+		Comment:     rv.Comment,
+		SubmittedAt: rv.SubmittedAt,
+	}
+}
+
+func (h *Handler) handlePrGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	details, err := h.PrService.Get(ctx, prID)
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	reviewers := make([]PrReviewerDetailsDTO, 0, len(details.Reviewers))
+	for _, rev := range details.Reviewers {
+		dto := PrReviewerDetailsDTO{
+			PrUserDTO: toPrUserDTO(rev.User),
+		}
+		if !rev.AssignedAt.IsZero() {
+			assignedAt := rev.AssignedAt
+			dto.AssignedAt = &assignedAt
+		}
+		if rev.Review != nil {
+			review := toReviewDTO(*rev.Review)
+			dto.Review = &review
+		}
+		reviewers = append(reviewers, dto)
+	}
+
+	resp := PrGetResponse{
+		Pr: PrDetailsDTO{
+			PrDTO:     toPrDTO(details.PullRequest),
+			Author:    toPrUserDTO(details.Author),
+			Reviewers: reviewers,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toPrUserDTO(u domain.User) PrUserDTO {
+	return PrUserDTO{
+		UserID:   u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+	}
+}
+
+func (h *Handler) handlePrList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	filter, err := parsePrFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	prs, next, err := h.PrService.List(ctx, filter)
+	if errors.Is(err, service.ErrInvalidPrFilter) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	items := make([]PrListItemDTO, 0, len(prs))
+	for _, pr := range prs {
+		items = append(items, PrListItemDTO{
+			PrID:         pr.ID,
+			Name:         pr.Name,
+			AuthorID:     pr.AuthorID,
+			Status:       string(pr.Status),
+			ReviewersIDs: append([]string{}, pr.ReviewersIDs...),
+			Labels:       append([]string{}, pr.Labels...),
+			CreatedAt:    pr.CreatedAt,
+			MergedAt:     pr.MergedAt,
+			ClosedAt:     pr.ClosedAt,
+		})
+	}
+
+	resp := PrListResponse{
+		PullRequests: items,
+	}
+	if next != nil {
+		cursor := encodePrCursor(*next)
+		resp.NextCursor = &cursor
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func parsePrFilter(r *http.Request) (domain.PrFilter, error) {
+	q := r.URL.Query()
+	filter := domain.PrFilter{
+		AuthorID:   q.Get("author_id"),
+		ReviewerID: q.Get("reviewer_id"),
+		Team:       q.Get("team_name"),
+		Name:       q.Get("name"),
+	}
+
+	// status may be repeated or comma separated.
+	for _, raw := range q["status"] {
+		for _, st := range strings.Split(raw, ",") {
+			if st = strings.TrimSpace(st); st != "" {
+				filter.Statuses = append(filter.Statuses, domain.PrStatus(strings.ToUpper(st)))
+			}
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.PrFilter{}, fmt.Errorf("%s must be an RFC 3339 timestamp", p.name)
+		}
+		*p.dst = &t
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := decodePrCursor(raw)
+		if err != nil {
+			return domain.PrFilter{}, errors.New("cursor is invalid")
+		}
+		filter.After = &cursor
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return domain.PrFilter{}, errors.New("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+// encodePrCursor makes an opaque cursor out of the sort key of a pull request.
+// created_at is truncated to microseconds, the precision Postgres stores,
+// so the cursor compares equal to the row it was made from.
+func encodePrCursor(c domain.PrCursor) string {
+	raw := c.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano) + "\n" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePrCursor(s string) (domain.PrCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.PrCursor{}, err
+	}
+	at, id, ok := strings.Cut(string(raw), "\n")
+	if !ok || id == "" {
+		return domain.PrCursor{}, errors.New("malformed cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return domain.PrCursor{}, err
+	}
+	return domain.PrCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+func (h *Handler) handlePrHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	history, err := h.PrService.History(ctx, prID)
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	historyDTO := make([]PrTransitionDTO, 0, len(history))
+	for _, t := range history {
+		transition := PrTransitionDTO{
+			ToStatus:  string(t.To),
+			Actor:     t.Actor,
+			Reason:    t.Reason,
+			ChangedAt: t.At,
+		}
+		if t.From != "" {
+			from := string(t.From)
+			transition.FromStatus = &from
+		}
+		historyDTO = append(historyDTO, transition)
+	}
+
+	resp := PrHistoryResponse{
+		PrID:    prID,
+		History: historyDTO,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handlePrEscalations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	escalations, err := h.PrService.Escalations(ctx, prID)
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	escalationsDTO := make([]EscalationDTO, 0, len(escalations))
+	for _, e := range escalations {
+		escalationsDTO = append(escalationsDTO, EscalationDTO{
+			ReviewerID:   e.ReviewerID,
+			Action:       e.Action,
+			ReplacedBy:   e.ReplacedBy,
+			Note:         e.Note,
+			SLAStartedAt: e.SLAStartedAt,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+
+	resp := PrEscalationsResponse{
+		PrID:        prID,
+		Escalations: escalationsDTO,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleAssignmentExplain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	assignments, err := h.PrService.ExplainAssignment(ctx, prID)
+	if errors.Is(err, service.ErrPrNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pr not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	reviewersDTO := make([]ReviewerRationaleDTO, 0, len(assignments))
+	for _, a := range assignments {
+		rationale := ReviewerRationaleDTO{
+			UserID:         a.UserID,
+			Strategy:       a.Strategy,
+			Reason:         a.Reason,
+			FallbackTeam:   a.FallbackTeam,
+			CandidatePool:  append([]string{}, a.CandidatePool...),
+			ReplacedUserID: a.ReplacedUserID,
+		}
+		if !a.AssignedAt.IsZero() {
+			assignedAt := a.AssignedAt
+			rationale.AssignedAt = &assignedAt
+		}
+		reviewersDTO = append(reviewersDTO, rationale)
+	}
+
+	resp := PrAssignmentExplainResponse{
+		PrID:      prID,
+		Reviewers: reviewersDTO,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+func (h *Handler) handlePrPreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req PrReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.AuthorID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "author_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	assignment, err := h.PrService.PreviewAssignment(ctx, service.CreatePrInput{
+		ID:           req.PrID,
+		Name:         req.Name,
+		AuthorID:     req.AuthorID,
+		ChangedFiles: req.ChangedFiles,
+		Labels:       req.Labels,
+		Seed:         req.Seed,
+	})
+	if errors.Is(err, service.ErrPrAlreadyExists) {
+		writeError(w, http.StatusConflict, "PR_EXISTS", "PR id already exists")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if errors.Is(err, service.ErrReviewersAtCapacity) {
+		writeError(w, http.StatusConflict, "REVIEWERS_AT_CAPACITY", "all candidate reviewers are at capacity")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	proposed := make([]ProposedReviewerDTO, 0, len(assignment.Reviewers))
+	for _, rev := range assignment.Reviewers {
+		proposed = append(proposed, ProposedReviewerDTO{
+			UserID:       rev.User.ID,
+			Username:     rev.User.Username,
+			TeamName:     rev.User.TeamName,
+			Strategy:     rev.Strategy,
+			Reason:       rev.Reason,
+			FallbackTeam: rev.FallbackTeam,
+		})
+	}
+
+	resp := PrPreviewResponse{
+		AuthorID:          req.AuthorID,
+		CandidatePool:     append([]string{}, assignment.CandidatePool...),
+		ProposedReviewers: proposed,
+		Assignment:        toAssignmentDTO(assignment),
+		Seed:              *assignment.Seed,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toAssignmentDTO(a service.Assignment) AssignmentDTO {
+	fallbackReviewers := make([]string, 0)
+	for _, rev := range a.Reviewers {
+		if rev.FromFallback() {
+			fallbackReviewers = append(fallbackReviewers, rev.User.ID)
+		}
+	}
+
+	return AssignmentDTO{
+		MinReviewers:      a.MinReviewers,
+		MaxReviewers:      a.MaxReviewers,
+		Assigned:          len(a.Reviewers),
+		Understaffed:      a.Understaffed(),
+		FallbackReviewers: fallbackReviewers,
+		OwnerReviewers:    a.IDsWithReason(service.ReasonCodeOwner),
+		TagReviewers:      a.IDsWithReason(service.ReasonTagMatch),
+		OverCapacity:      a.IDsWithReason(service.ReasonOverCapacity),
+	}
+}
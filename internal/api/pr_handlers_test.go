@@ -0,0 +1,77 @@
+package api
+
+import (
+	"PR_project/internal/domain"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestPrCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		id        string
+		want      time.Time
+	}{
+		{
+			name:      "whole seconds",
+			createdAt: time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC),
+			id:        "pr-1001",
+			want:      time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC),
+		},
+		{
+			name:      "microseconds are kept",
+			createdAt: time.Date(2025, 10, 24, 12, 34, 56, 123456000, time.UTC),
+			id:        "pr-1002",
+			want:      time.Date(2025, 10, 24, 12, 34, 56, 123456000, time.UTC),
+		},
+		{
+			name:      "nanoseconds are truncated",
+			createdAt: time.Date(2025, 10, 24, 12, 34, 56, 123456789, time.UTC),
+			id:        "pr-1003",
+			want:      time.Date(2025, 10, 24, 12, 34, 56, 123456000, time.UTC),
+		},
+		{
+			name:      "other time zones are normalized",
+			createdAt: time.Date(2025, 10, 24, 15, 34, 56, 0, time.FixedZone("MSK", 3*60*60)),
+			id:        "pr-1004",
+			want:      time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC),
+		},
+		{
+			name:      "id with separators",
+			createdAt: time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC),
+			id:        "team/pr 5\nx",
+			want:      time.Date(2025, 10, 24, 12, 34, 56, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePrCursor(encodePrCursor(domain.PrCursor{CreatedAt: tt.createdAt, ID: tt.id}))
+			if err != nil {
+				t.Fatalf("decodePrCursor: %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.want) || got.ID != tt.id {
+				t.Errorf("round trip = {%s %q}, want {%s %q}", got.CreatedAt, got.ID, tt.want, tt.id)
+			}
+		})
+	}
+}
+
+func TestDecodePrCursorRejectsMalformed(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	for name, cursor := range map[string]string{
+		"not base64":   "!!!",
+		"no separator": encode("2025-10-24T12:34:56Z"),
+		"empty id":     encode("2025-10-24T12:34:56Z\n"),
+		"bad time":     encode("yesterday\npr-1"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodePrCursor(cursor); err == nil {
+				t.Errorf("decodePrCursor(%q) succeeded, want an error", cursor)
+			}
+		})
+	}
+}
@@ -5,13 +5,47 @@ import "net/http"
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/team/add", h.handleTeamAdd)
 	mux.HandleFunc("/team/get", h.handleTeamGet)
+	mux.HandleFunc("/team/addMember", h.handleTeamAddMember)
+	mux.HandleFunc("/team/updateMember", h.handleTeamUpdateMember)
+	mux.HandleFunc("/team/removeMember", h.handleTeamRemoveMember)
+	mux.HandleFunc("/team/settings", h.handleTeamSettings)
+	mux.HandleFunc("/team/codeowners", h.handleTeamCodeowners)
 
+	mux.HandleFunc("/users/get", h.handleUserGet)
 	mux.HandleFunc("/users/setIsActive", h.handleUserSetIsActive)
+	mux.HandleFunc("/users/bulkDeactivate", h.handleUserBulkDeactivate)
+	mux.HandleFunc("/users/moveTeam", h.handleUserMoveTeam)
 	mux.HandleFunc("/users/getReview", h.handleGetReviews)
+	mux.HandleFunc("/users/setTags", h.handleUserSetTags)
+	mux.HandleFunc("/users/addTags", h.handleUserAddTags)
+	mux.HandleFunc("/users/removeTags", h.handleUserRemoveTags)
+	mux.HandleFunc("/users/addAbsence", h.handleUserAddAbsence)
+	mux.HandleFunc("/users/absences", h.handleUserAbsences)
+	mux.HandleFunc("/users/cancelAbsence", h.handleUserCancelAbsence)
+	mux.HandleFunc("/users/setCapacity", h.handleUserSetCapacity)
 
 	mux.HandleFunc("/pullRequest/create", h.handlePrAdd)
+	mux.HandleFunc("/pullRequest/preview", h.handlePrPreview)
+	mux.HandleFunc("/pullRequest/get", h.handlePrGet)
+	mux.HandleFunc("/pullRequest/list", h.handlePrList)
+	mux.HandleFunc("/pullRequest/ready", h.handlePrReady)
+	mux.HandleFunc("/pullRequest/review", h.handlePrReview)
 	mux.HandleFunc("/pullRequest/merge", h.handleMerge)
+	mux.HandleFunc("/pullRequest/close", h.handlePrClose)
+	mux.HandleFunc("/pullRequest/reopen", h.handlePrReopen)
 	mux.HandleFunc("/pullRequest/reassign", h.handleReassign)
+	mux.HandleFunc("/pullRequest/history", h.handlePrHistory)
+	mux.HandleFunc("/pullRequest/escalations", h.handlePrEscalations)
+	mux.HandleFunc("/pullRequest/assignment-explain", h.handleAssignmentExplain)
+
+	mux.HandleFunc("/audit", h.handleAuditList)
+	mux.HandleFunc("/audit/export", h.handleAuditExport)
+
+	mux.HandleFunc("/webhooks/subscribe", h.handleWebhookSubscribe)
+	mux.HandleFunc("/webhooks/list", h.handleWebhookList)
+	mux.HandleFunc("/webhooks/delete", h.handleWebhookDelete)
+	mux.HandleFunc("/webhooks/deliveries", h.handleWebhookDeliveries)
+	mux.HandleFunc("/webhooks/redeliver", h.handleWebhookRedeliver)
 
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
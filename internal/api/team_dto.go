@@ -1,16 +1,125 @@
 package api
 
+import "encoding/json"
+
 type TeamDTO struct {
 	TeamName string          `json:"team_name"`
 	Members  []TeamMemberDTO `json:"members"`
 }
 
 type TeamMemberDTO struct {
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	IsActive bool     `json:"is_active"`
+	Tags     []string `json:"tags"`
+}
+
+type TeamAddReqDTO struct {
+	TeamName string          `json:"team_name"`
+	Members  []TeamMemberDTO `json:"members"`
+	// OnConflict is fail (default), move or skip.
+	OnConflict string `json:"on_conflict"`
+}
+
+// TeamConflictDTO is a requested member who belongs to another team.
+type TeamConflictDTO struct {
 	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	TeamName string `json:"team_name"`
+}
+
+type TeamConflictErrorBody struct {
+	Error struct {
+		Code      string            `json:"code"`
+		Message   string            `json:"message"`
+		Conflicts []TeamConflictDTO `json:"conflicts"`
+	} `json:"error"`
 }
 
 type TeamAddResponse struct {
-	Team TeamDTO `json:"team"`
+	Team    TeamDTO           `json:"team"`
+	Moved   []TeamConflictDTO `json:"moved,omitempty"`
+	Skipped []TeamConflictDTO `json:"skipped,omitempty"`
+}
+
+type TeamMemberAddReqDTO struct {
+	TeamName string        `json:"team_name"`
+	Member   TeamMemberDTO `json:"member"`
+}
+
+type TeamMemberUpdateReqDTO struct {
+	TeamName string  `json:"team_name"`
+	UserID   string  `json:"user_id"`
+	Username *string `json:"username"`
+	IsActive *bool   `json:"is_active"`
+}
+
+type TeamMemberRemoveReqDTO struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type TeamMemberRemoveResponse struct {
+	User          UserDTO             `json:"user"`
+	Reassignments []ReviewHandoverDTO `json:"reassignments"`
+	// AuthoredPullRequests are the member's OPEN and DRAFT pull requests, left as they are.
+	AuthoredPullRequests []string `json:"authored_pull_requests"`
+}
+
+type TeamSettingsDTO struct {
+	TeamName          string   `json:"team_name"`
+	MinReviewers      int      `json:"min_reviewers"`
+	MaxReviewers      int      `json:"max_reviewers"`
+	FallbackTeams     []string `json:"fallback_teams"`
+	MaxOpenReviews    *int     `json:"max_open_reviews"`
+	CapacityPolicy    string   `json:"capacity_policy"`
+	ApprovalPolicy    string   `json:"approval_policy"`
+	RequiredApprovals int      `json:"required_approvals"`
+	ReviewSLAMinutes  *int     `json:"review_sla_minutes"`
+	SLAAction         string   `json:"sla_action"`
+}
+
+// TeamSettingsUpdateDTO is a partial settings update: omitted fields keep
+// their current value.
+type TeamSettingsUpdateDTO struct {
+	TeamName          string      `json:"team_name"`
+	MinReviewers      *int        `json:"min_reviewers"`
+	MaxReviewers      *int        `json:"max_reviewers"`
+	FallbackTeams     *[]string   `json:"fallback_teams"`
+	MaxOpenReviews    nullableInt `json:"max_open_reviews"`
+	CapacityPolicy    *string     `json:"capacity_policy"`
+	ApprovalPolicy    *string     `json:"approval_policy"`
+	RequiredApprovals *int        `json:"required_approvals"`
+	ReviewSLAMinutes  nullableInt `json:"review_sla_minutes"`
+	SLAAction         *string     `json:"sla_action"`
+}
+
+// nullableInt tells an omitted field from an explicit null.
+type nullableInt struct {
+	Set   bool
+	Value *int
+}
+
+func (n *nullableInt) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	return json.Unmarshal(data, &n.Value)
+}
+
+type TeamSettingsResponse struct {
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+type CodeownersReqDTO struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+}
+
+type OwnershipRuleDTO struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type CodeownersDTO struct {
+	TeamName string             `json:"team_name"`
+	Content  string             `json:"content"`
+	Rules    []OwnershipRuleDTO `json:"rules"`
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"PR_project/internal/domain"
 	"PR_project/internal/service"
 	"encoding/json"
 	"errors"
@@ -13,7 +14,7 @@ func (h *Handler) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req TeamDTO
+	var req TeamAddReqDTO
 
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
@@ -33,48 +34,90 @@ func (h *Handler) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if req.OnConflict != "" && !domain.ValidOnConflict(req.OnConflict) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "on_conflict must be one of fail, move, skip")
+		return
+	}
+
 	membersInput := make([]service.TeamMemberInput, 0, len(req.Members))
 	for _, member := range req.Members {
 		membersInput = append(membersInput, service.TeamMemberInput{
 			UserID:   member.UserID,
 			Username: member.Username,
 			IsActive: member.IsActive,
+			Tags:     member.Tags,
 		})
 	}
 
 	ctx := r.Context()
-	team, users, err := h.TeamService.CreateTeam(ctx, req.TeamName, membersInput)
+	created, err := h.TeamService.CreateTeam(ctx, req.TeamName, membersInput, req.OnConflict)
 	if errors.Is(err, service.ErrTeamAlreadyExists) {
 		writeError(w, http.StatusBadRequest, "TEAM_EXISTS", "team_name already exists")
 		return
 	}
+	var conflict *service.MemberConflictError
+	if errors.As(err, &conflict) {
+		writeTeamConflict(w, conflict.Conflicts)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidOnConflict) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "on_conflict must be one of fail, move, skip")
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
 		return
 	}
 
-	membersDTO := make([]TeamMemberDTO, 0, len(users))
-	for _, u := range users {
+	membersDTO := make([]TeamMemberDTO, 0, len(created.Members))
+	for _, u := range created.Members {
 		membersDTO = append(membersDTO, TeamMemberDTO{
 			UserID:   u.ID,
 			Username: u.Username,
 			IsActive: u.IsActive,
+			Tags:     append([]string{}, u.Tags...),
 		})
 	}
 
 	respTeam := TeamDTO{
-		TeamName: team.Name,
+		TeamName: created.Team.Name,
 		Members:  membersDTO,
 	}
 
 	resp := TeamAddResponse{
-		Team: respTeam,
+		Team:    respTeam,
+		Moved:   toTeamConflictDTOs(created.Moved),
+		Skipped: toTeamConflictDTOs(created.Skipped),
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// writeTeamConflict reports the requested members who belong to other teams.
+func writeTeamConflict(w http.ResponseWriter, conflicts []domain.User) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+
+	var body TeamConflictErrorBody
+	body.Error.Code = "USER_IN_OTHER_TEAM"
+	body.Error.Message = "some members already belong to another team"
+	body.Error.Conflicts = toTeamConflictDTOs(conflicts)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func toTeamConflictDTOs(users []domain.User) []TeamConflictDTO {
+	if len(users) == 0 {
+		return nil
+	}
+	out := make([]TeamConflictDTO, 0, len(users))
+	for _, u := range users {
+		out = append(out, TeamConflictDTO{UserID: u.ID, TeamName: u.TeamName})
+	}
+	return out
+}
+
 func (h *Handler) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
@@ -104,6 +147,7 @@ func (h *Handler) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 			UserID:   u.ID,
 			Username: u.Username,
 			IsActive: u.IsActive,
+			Tags:     append([]string{}, u.Tags...),
 		})
 	}
 
@@ -116,3 +160,331 @@ func (h *Handler) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(respTeam)
 }
+
+func (h *Handler) handleTeamAddMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req TeamMemberAddReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.TeamName == "" || req.Member.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and member.user_id are required")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.TeamService.AddMember(ctx, req.TeamName, service.TeamMemberInput{
+		UserID:   req.Member.UserID,
+		Username: req.Member.Username,
+		IsActive: req.Member.IsActive,
+		Tags:     req.Member.Tags,
+	})
+	if !writeMemberError(w, err) {
+		return
+	}
+
+	resp := UserAddResponse{
+		User: toUserDTO(user),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleTeamUpdateMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req TeamMemberUpdateReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+	if req.Username == nil && req.IsActive == nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "username or is_active is required")
+		return
+	}
+
+	ctx := r.Context()
+	user, handovers, err := h.TeamService.UpdateMember(ctx, req.TeamName, req.UserID, service.MemberUpdate{
+		Username: req.Username,
+		IsActive: req.IsActive,
+	})
+	if !writeMemberError(w, err) {
+		return
+	}
+
+	resp := UserSetIsActiveResponse{
+		User:          toUserDTO(user),
+		Reassignments: toReviewHandoverDTOs(handovers),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleTeamRemoveMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req TeamMemberRemoveReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.TeamService.RemoveMember(ctx, req.TeamName, req.UserID)
+	if !writeMemberError(w, err) {
+		return
+	}
+
+	resp := TeamMemberRemoveResponse{
+		User:                 toUserDTO(report.Users[0]),
+		Reassignments:        toReviewHandoverDTOs(report.Handovers),
+		AuthoredPullRequests: append([]string{}, report.AuthoredPullRequestIDs...),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeMemberError maps the errors of the team membership operations and
+// reports whether the handler may go on.
+func writeMemberError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, service.ErrInvalidMember):
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid team member")
+	case errors.Is(err, service.ErrTeamNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+	case errors.Is(err, service.ErrUserNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+	case errors.Is(err, service.ErrMemberNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user is not a member of the team")
+	case errors.Is(err, service.ErrMemberExists):
+		writeError(w, http.StatusConflict, "MEMBER_EXISTS", "user is already a member of the team")
+	case errors.Is(err, service.ErrUserInOtherTeam):
+		writeError(w, http.StatusConflict, "USER_IN_OTHER_TEAM", "user belongs to another team")
+	default:
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+	}
+	return false
+}
+
+func (h *Handler) handleTeamSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleTeamSettingsGet(w, r)
+	case http.MethodPost:
+		h.handleTeamSettingsUpdate(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET and POST are allowed")
+	}
+}
+
+func (h *Handler) handleTeamSettingsGet(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+	settings, err := h.TeamService.GetSettings(ctx, teamName)
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := TeamSettingsResponse{
+		Settings: toTeamSettingsDTO(settings),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleTeamSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	var req TeamSettingsUpdateDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+	settings, err := h.TeamService.UpdateSettings(ctx, service.SettingsUpdate{
+		TeamName:          req.TeamName,
+		MinReviewers:      req.MinReviewers,
+		MaxReviewers:      req.MaxReviewers,
+		FallbackTeams:     req.FallbackTeams,
+		MaxOpenReviews:    service.NullableInt(req.MaxOpenReviews),
+		CapacityPolicy:    req.CapacityPolicy,
+		ApprovalPolicy:    req.ApprovalPolicy,
+		RequiredApprovals: req.RequiredApprovals,
+		ReviewSLAMinutes:  service.NullableInt(req.ReviewSLAMinutes),
+		SLAAction:         req.SLAAction,
+	})
+	if errors.Is(err, service.ErrInvalidSettings) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST",
+			"min_reviewers must be >= 0 and <= max_reviewers, fallback_teams must be distinct other teams, "+
+				"max_open_reviews must be positive, capacity_policy must be ASSIGN_ANYWAY, ASSIGN_FEWER or REJECT, "+
+				"approval_policy must be NONE, ALL_APPROVED or MIN_APPROVALS, required_approvals must be positive, "+
+				"review_sla_minutes must be positive, sla_action must be ESCALATE or REASSIGN")
+		return
+	}
+	if errors.Is(err, service.ErrFallbackNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "fallback team not found")
+		return
+	}
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := TeamSettingsResponse{
+		Settings: toTeamSettingsDTO(settings),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toTeamSettingsDTO(s domain.TeamSettings) TeamSettingsDTO {
+	return TeamSettingsDTO{
+		TeamName:          s.TeamName,
+		MinReviewers:      s.MinReviewers,
+		MaxReviewers:      s.MaxReviewers,
+		FallbackTeams:     append([]string{}, s.FallbackTeams...),
+		MaxOpenReviews:    s.MaxOpenReviews,
+		CapacityPolicy:    s.CapacityPolicy,
+		ApprovalPolicy:    s.ApprovalPolicy,
+		RequiredApprovals: s.RequiredApprovals,
+		ReviewSLAMinutes:  s.ReviewSLAMinutes,
+		SLAAction:         s.SLAAction,
+	}
+}
+
+func (h *Handler) handleTeamCodeowners(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.handleTeamCodeownersGet(w, r)
+	case http.MethodPost:
+		h.handleTeamCodeownersUpload(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET and POST are allowed")
+	}
+}
+
+func (h *Handler) handleTeamCodeownersGet(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+	rules, err := h.TeamService.GetOwnershipRules(ctx, teamName)
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(toCodeownersDTO(teamName, rules))
+}
+
+func (h *Handler) handleTeamCodeownersUpload(w http.ResponseWriter, r *http.Request) {
+	var req CodeownersReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+	rules, err := h.TeamService.SetCodeowners(ctx, req.TeamName, req.Content)
+	if errors.Is(err, domain.ErrInvalidCodeowners) {
+		writeError(w, http.StatusBadRequest, "INVALID_CODEOWNERS", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(toCodeownersDTO(req.TeamName, rules))
+}
+
+func toCodeownersDTO(teamName string, rules []domain.OwnershipRule) CodeownersDTO {
+	rulesDTO := make([]OwnershipRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		rulesDTO = append(rulesDTO, OwnershipRuleDTO{
+			Pattern: rule.Pattern,
+			Owners:  append([]string{}, rule.Owners...),
+		})
+	}
+
+	return CodeownersDTO{
+		TeamName: teamName,
+		Content:  domain.FormatCodeowners(rules),
+		Rules:    rulesDTO,
+	}
+}
@@ -1,22 +1,127 @@
 package api
 
+import "time"
+
 type UserReqDTO struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
 
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	IsActive bool     `json:"is_active"`
+	Tags     []string `json:"tags"`
+	// MaxOpenReviews is the personal override, null means the team default applies.
+	MaxOpenReviews *int `json:"max_open_reviews"`
+}
+
+type UserTagsReqDTO struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
 }
 
 type UserAndPrDTO struct {
-	UserID string       `json:"user_id"`
-	Prs    []PrShortDTO `json:"pull_requests"`
+	UserID      string       `json:"user_id"`
+	Prs         []PrShortDTO `json:"pull_requests"`
+	OpenReviews int          `json:"open_reviews"`
+	Capacity    *int         `json:"capacity"`
+	AtCapacity  bool         `json:"at_capacity"`
+}
+
+type UserCapacityReqDTO struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
 }
 
 type UserAddResponse struct {
 	User UserDTO `json:"user"`
 }
+
+const (
+	HandoverReassigned = "REASSIGNED"
+	HandoverUnassigned = "UNASSIGNED"
+)
+
+type ReviewHandoverDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	// NewReviewerID is null when nobody could take the review over.
+	NewReviewerID *string `json:"new_reviewer_id"`
+	Outcome       string  `json:"outcome"`
+}
+
+type UserSetIsActiveResponse struct {
+	User UserDTO `json:"user"`
+	// Reassignments lists what happened to the open reviews of a deactivated user.
+	Reassignments []ReviewHandoverDTO `json:"reassignments,omitempty"`
+}
+
+type AbsenceReqDTO struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type AbsenceCancelReqDTO struct {
+	AbsenceID int64 `json:"absence_id"`
+}
+
+type AbsenceDTO struct {
+	AbsenceID   int64      `json:"absence_id"`
+	UserID      string     `json:"user_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Reason      string     `json:"reason"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+	IsCurrent   bool       `json:"is_current"`
+}
+
+type AbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+type AbsenceListResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
+type BulkDeactivateReqDTO struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+	// AllowOtherTeams lets users from other teams take over the reviews.
+	AllowOtherTeams bool `json:"allow_other_teams"`
+}
+
+type BulkDeactivateResponse struct {
+	Users         []UserDTO           `json:"users"`
+	Reassignments []ReviewHandoverDTO `json:"reassignments"`
+}
+
+type UserMoveTeamReqDTO struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	// ReassignReviews hands the user's open reviews in the old team over to it.
+	ReassignReviews bool `json:"reassign_reviews"`
+}
+
+type UserMoveTeamResponse struct {
+	User          UserDTO             `json:"user"`
+	Reassignments []ReviewHandoverDTO `json:"reassignments"`
+}
+
+type TeamMembershipDTO struct {
+	TeamName string     `json:"team_name"`
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at"`
+	Reason   string     `json:"reason"`
+	Actor    string     `json:"actor,omitempty"`
+}
+
+type UserProfileResponse struct {
+	User        UserDTO             `json:"user"`
+	TeamHistory []TeamMembershipDTO `json:"team_history"`
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"PR_project/internal/domain"
 	"PR_project/internal/service"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request) {
@@ -27,7 +31,7 @@ func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request)
 	}
 
 	ctx := r.Context()
-	user, err := h.UserService.SetIsActive(ctx, req.UserID, req.IsActive)
+	user, handovers, err := h.UserService.SetIsActive(ctx, req.UserID, req.IsActive)
 	if errors.Is(err, service.ErrUserNotFound) {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
 		return
@@ -37,15 +41,63 @@ func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	respUser := UserDTO{
-		UserID:   user.ID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
+	resp := UserSetIsActiveResponse{
+		User:          toUserDTO(user),
+		Reassignments: toReviewHandoverDTOs(handovers),
 	}
 
-	resp := UserAddResponse{
-		User: respUser,
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleUserBulkDeactivate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req BulkDeactivateReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if (req.TeamName == "") == (len(req.UserIDs) == 0) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "exactly one of team_name and user_ids is required")
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.UserService.Deactivate(ctx, domain.DeactivationTarget{
+		TeamName: req.TeamName,
+		UserIDs:  req.UserIDs,
+		AnyTeam:  req.AllowOtherTeams,
+	})
+	if errors.Is(err, service.ErrInvalidDeactivation) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid deactivation request")
+		return
+	}
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := BulkDeactivateResponse{
+		Users:         make([]UserDTO, 0, len(report.Users)),
+		Reassignments: toReviewHandoverDTOs(report.Handovers),
+	}
+	for _, u := range report.Users {
+		resp.Users = append(resp.Users, toUserDTO(u))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -53,6 +105,113 @@ func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request)
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+func (h *Handler) handleUserMoveTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req UserMoveTeamReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.UserID == "" || req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id and team_name are required")
+		return
+	}
+
+	ctx := r.Context()
+	user, handovers, err := h.UserService.MoveTeam(ctx, req.UserID, req.TeamName, req.ReassignReviews)
+	if errors.Is(err, service.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if errors.Is(err, service.ErrMemberExists) {
+		writeError(w, http.StatusConflict, "MEMBER_EXISTS", "user is already a member of the team")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := UserMoveTeamResponse{
+		User:          toUserDTO(user),
+		Reassignments: toReviewHandoverDTOs(handovers),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleUserGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	profile, err := h.UserService.GetProfile(ctx, userID)
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := UserProfileResponse{
+		User:        toUserDTO(profile.User),
+		TeamHistory: make([]TeamMembershipDTO, 0, len(profile.Memberships)),
+	}
+	for _, m := range profile.Memberships {
+		resp.TeamHistory = append(resp.TeamHistory, TeamMembershipDTO{
+			TeamName: m.TeamName,
+			JoinedAt: m.JoinedAt,
+			LeftAt:   m.LeftAt,
+			Reason:   m.Reason,
+			Actor:    m.Actor,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toReviewHandoverDTOs(handovers []domain.ReviewHandover) []ReviewHandoverDTO {
+	out := make([]ReviewHandoverDTO, 0, len(handovers))
+	for _, h := range handovers {
+		dto := ReviewHandoverDTO{
+			PullRequestID: h.PullRequestID,
+			OldReviewerID: h.FromUserID,
+			Outcome:       HandoverUnassigned,
+		}
+		if !h.Unassigned() {
+			newReviewerID := h.To.UserID
+			dto.NewReviewerID = &newReviewerID
+			dto.Outcome = HandoverReassigned
+		}
+		out = append(out, dto)
+	}
+	return out
+}
+
 func (h *Handler) handleGetReviews(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
@@ -65,8 +224,18 @@ func (h *Handler) handleGetReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	includeClosed := false
+	if raw := r.URL.Query().Get("include_closed"); raw != "" {
+		var err error
+		includeClosed, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "include_closed must be a boolean")
+			return
+		}
+	}
+
 	ctx := r.Context()
-	prs, err := h.UserService.GetReviews(ctx, userID)
+	prs, err := h.UserService.GetReviews(ctx, userID, includeClosed)
 	if errors.Is(err, service.ErrUserNotFound) {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
 		return
@@ -76,22 +245,274 @@ func (h *Handler) handleGetReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	load, err := h.UserService.GetReviewLoad(ctx, userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
 	prsDTO := make([]PrShortDTO, 0, len(prs))
 	for _, pr := range prs {
 		prsDTO = append(prsDTO, PrShortDTO{
 			PrID:     pr.ID,
 			Name:     pr.Name,
 			AuthorID: pr.AuthorID,
-			Status:   pr.Status,
+			Status:   string(pr.Status),
 		})
 	}
 
 	respReviewrs := UserAndPrDTO{
-		UserID: userID,
-		Prs:    prsDTO,
+		UserID:      userID,
+		Prs:         prsDTO,
+		OpenReviews: load.OpenReviews,
+		Capacity:    load.Capacity,
+		AtCapacity:  load.Capacity != nil && load.OpenReviews >= *load.Capacity,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(respReviewrs)
 }
+
+func (h *Handler) handleUserSetTags(w http.ResponseWriter, r *http.Request) {
+	h.handleUserTags(w, r, h.UserService.SetTags)
+}
+
+func (h *Handler) handleUserAddTags(w http.ResponseWriter, r *http.Request) {
+	h.handleUserTags(w, r, h.UserService.AddTags)
+}
+
+func (h *Handler) handleUserRemoveTags(w http.ResponseWriter, r *http.Request) {
+	h.handleUserTags(w, r, h.UserService.RemoveTags)
+}
+
+func (h *Handler) handleUserTags(
+	w http.ResponseWriter,
+	r *http.Request,
+	update func(ctx context.Context, userID string, tags []string) (domain.User, error),
+) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req UserTagsReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := update(ctx, req.UserID, req.Tags)
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := UserAddResponse{
+		User: toUserDTO(user),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toUserDTO(u domain.User) UserDTO {
+	return UserDTO{
+		UserID:         u.ID,
+		Username:       u.Username,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		Tags:           append([]string{}, u.Tags...),
+		MaxOpenReviews: u.MaxOpenReviews,
+	}
+}
+
+func (h *Handler) handleUserAddAbsence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req AbsenceReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "starts_at and ends_at are required")
+		return
+	}
+
+	ctx := r.Context()
+	absence, err := h.UserService.CreateAbsence(ctx, domain.Absence{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if errors.Is(err, service.ErrInvalidAbsence) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "ends_at must be after starts_at and in the future")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := AbsenceResponse{
+		Absence: toAbsenceDTO(absence, time.Now()),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleUserAbsences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	absences, err := h.UserService.ListAbsences(ctx, userID)
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	now := time.Now()
+	absencesDTO := make([]AbsenceDTO, 0, len(absences))
+	for _, a := range absences {
+		absencesDTO = append(absencesDTO, toAbsenceDTO(a, now))
+	}
+
+	resp := AbsenceListResponse{
+		UserID:   userID,
+		Absences: absencesDTO,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleUserCancelAbsence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req AbsenceCancelReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.AbsenceID == 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "absence_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	absence, err := h.UserService.CancelAbsence(ctx, req.AbsenceID)
+	if errors.Is(err, service.ErrAbsenceNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "absence not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := AbsenceResponse{
+		Absence: toAbsenceDTO(absence, time.Now()),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toAbsenceDTO(a domain.Absence, now time.Time) AbsenceDTO {
+	return AbsenceDTO{
+		AbsenceID:   a.ID,
+		UserID:      a.UserID,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		Reason:      a.Reason,
+		CreatedAt:   a.CreatedAt,
+		CancelledAt: a.CancelledAt,
+		IsCurrent:   a.Covers(now),
+	}
+}
+
+func (h *Handler) handleUserSetCapacity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req UserCapacityReqDTO
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.UserService.SetMaxOpenReviews(ctx, req.UserID, req.MaxOpenReviews)
+	if errors.Is(err, service.ErrInvalidCapacity) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "max_open_reviews must be positive or null")
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := UserAddResponse{
+		User: toUserDTO(user),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
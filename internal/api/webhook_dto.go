@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookSubscribeReqDTO struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+type WebhookSubscriptionDTO struct {
+	SubscriptionID int64     `json:"subscription_id"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	CreatedAt      time.Time `json:"created_at"`
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookSubscribeResponse struct {
+	Subscription WebhookSubscriptionDTO `json:"subscription"`
+}
+
+type WebhookListResponse struct {
+	Subscriptions []WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+type WebhookDeleteReqDTO struct {
+	SubscriptionID int64 `json:"subscription_id"`
+}
+
+type WebhookDeliveryDTO struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	RedeliveryOf   *int64          `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryDTO `json:"deliveries"`
+	// NextCursor is passed as cursor to fetch the next page, null on the last one.
+	NextCursor *string `json:"next_cursor"`
+}
+
+type WebhookRedeliverReqDTO struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+type WebhookRedeliverResponse struct {
+	Delivery WebhookDeliveryDTO `json:"delivery"`
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"PR_project/internal/domain"
+	"PR_project/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) handleWebhookSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req WebhookSubscribeReqDTO
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "url is required")
+		return
+	}
+
+	ctx := r.Context()
+	sub, err := h.WebhookService.Subscribe(ctx, domain.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if errors.Is(err, service.ErrInvalidWebhook) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "url must be an absolute http(s) URL and event_types must be known events")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	subDTO := toWebhookSubscriptionDTO(sub)
+	subDTO.Secret = sub.Secret
+	resp := WebhookSubscribeResponse{
+		Subscription: subDTO,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleWebhookList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	ctx := r.Context()
+	subs, err := h.WebhookService.ListSubscriptions(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	subsDTO := make([]WebhookSubscriptionDTO, 0, len(subs))
+	for _, sub := range subs {
+		subsDTO = append(subsDTO, toWebhookSubscriptionDTO(sub))
+	}
+
+	resp := WebhookListResponse{
+		Subscriptions: subsDTO,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleWebhookDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req WebhookDeleteReqDTO
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.SubscriptionID <= 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "subscription_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	err = h.WebhookService.Unsubscribe(ctx, req.SubscriptionID)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "subscription not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only GET is allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := domain.DeliveryFilter{
+		Status: q.Get("status"),
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int64
+	}{{"subscription_id", &filter.SubscriptionID}, {"cursor", &filter.BeforeID}} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v <= 0 {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", p.name+" is invalid")
+			return
+		}
+		*p.dst = v
+	}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	ctx := r.Context()
+	deliveries, next, err := h.WebhookService.ListDeliveries(ctx, filter)
+	if errors.Is(err, service.ErrInvalidDeliveryQuery) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "status must be PENDING, DELIVERED or FAILED and limit at most 500")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	deliveriesDTO := make([]WebhookDeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		deliveriesDTO = append(deliveriesDTO, toWebhookDeliveryDTO(d))
+	}
+
+	resp := WebhookDeliveriesResponse{
+		Deliveries: deliveriesDTO,
+	}
+	if next != 0 {
+		cursor := strconv.FormatInt(next, 10)
+		resp.NextCursor = &cursor
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) handleWebhookRedeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "only POST is allowed")
+		return
+	}
+
+	var req WebhookRedeliverReqDTO
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid JSON body")
+		return
+	}
+
+	if req.DeliveryID <= 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "delivery_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	delivery, err := h.WebhookService.Redeliver(ctx, req.DeliveryID)
+	if errors.Is(err, service.ErrDeliveryNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "delivery not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", "internal server error")
+		return
+	}
+
+	resp := WebhookRedeliverResponse{
+		Delivery: toWebhookDeliveryDTO(delivery),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func toWebhookSubscriptionDTO(sub domain.WebhookSubscription) WebhookSubscriptionDTO {
+	eventTypes := sub.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return WebhookSubscriptionDTO{
+		SubscriptionID: sub.ID,
+		URL:            sub.URL,
+		EventTypes:     eventTypes,
+		CreatedAt:      sub.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryDTO(d domain.WebhookDelivery) WebhookDeliveryDTO {
+	dto := WebhookDeliveryDTO{
+		DeliveryID:     d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		Payload:        rawOrNull(d.Payload),
+	}
+	if d.Status == domain.DeliveryPending {
+		dto.NextAttemptAt = &d.NextAttemptAt
+	}
+	return dto
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	DatabaseURL            string
+	ReviewerStrategy       string
+	TeamReviewerStrategies map[string]string
+	// MergeOverrideActors are the X-Actor-ID values allowed to merge
+	// with admin_override, nobody when empty.
+	MergeOverrideActors []string
+	// SLAScanInterval is how often overdue reviews are looked for,
+	// zero disables the SLA worker.
+	SLAScanInterval time.Duration
+	// WebhookDispatchInterval is how often due webhook deliveries are sent,
+	// zero disables the dispatcher.
+	WebhookDispatchInterval time.Duration
+	// OutboxPollInterval is how often the outbox is checked for events to
+	// publish, zero disables the outbox dispatcher.
+	OutboxPollInterval time.Duration
+}
+
+func Load() (Config, error) {
+	cfg := Config{
+		DatabaseURL:      os.Getenv("DATABASE_URL"),
+		ReviewerStrategy: os.Getenv("REVIEWER_STRATEGY"),
+	}
+	if cfg.DatabaseURL == "" {
+		return Config{}, errors.New("DATABASE_URL is not set")
+	}
+	if cfg.ReviewerStrategy == "" {
+		cfg.ReviewerStrategy = "random"
+	}
+
+	teamStrategies, err := parseTeamStrategies(os.Getenv("TEAM_REVIEWER_STRATEGIES"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.TeamReviewerStrategies = teamStrategies
+	cfg.MergeOverrideActors = parseList(os.Getenv("MERGE_OVERRIDE_ACTORS"))
+
+	cfg.SLAScanInterval, err = parseInterval("SLA_SCAN_INTERVAL", time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.WebhookDispatchInterval, err = parseInterval("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.OutboxPollInterval, err = parseInterval("OUTBOX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// parseInterval reads a non-negative duration such as "30s" from the
+// environment variable name, falling back to def when it is unset.
+func parseInterval(name string, def time.Duration) (time.Duration, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s: invalid duration %q", name, raw)
+	}
+	return d, nil
+}
+
+// parseList parses a comma-separated list such as "alice,bob".
+func parseList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTeamStrategies parses "backend=least_loaded,mobile=round_robin".
+func parseTeamStrategies(raw string) (map[string]string, error) {
+	strategies := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		team, strategy, ok := strings.Cut(pair, "=")
+		team = strings.TrimSpace(team)
+		strategy = strings.TrimSpace(strategy)
+		if !ok || team == "" || strategy == "" {
+			return nil, fmt.Errorf("TEAM_REVIEWER_STRATEGIES: invalid entry %q", pair)
+		}
+		strategies[team] = strategy
+	}
+	return strategies, nil
+}
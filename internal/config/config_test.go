@@ -0,0 +1,54 @@
+package config
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestLoadReviewerStrategies(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     string
+		teams        string
+		wantStrategy string
+		wantTeams    map[string]string
+		wantErr      bool
+	}{
+		{"defaults to random", "", "", "random", map[string]string{}, false},
+		{"default strategy", "round_robin", "", "round_robin", map[string]string{}, false},
+		{
+			name:         "per-team overrides",
+			strategy:     "random",
+			teams:        " backend = least_loaded , mobile=round_robin,",
+			wantStrategy: "random",
+			wantTeams:    map[string]string{"backend": "least_loaded", "mobile": "round_robin"},
+		},
+		{name: "missing strategy", teams: "backend=", wantErr: true},
+		{name: "missing separator", teams: "backend", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", "postgres://localhost/test")
+			t.Setenv("REVIEWER_STRATEGY", tt.strategy)
+			t.Setenv("TEAM_REVIEWER_STRATEGIES", tt.teams)
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Load() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.ReviewerStrategy != tt.wantStrategy {
+				t.Errorf("ReviewerStrategy = %q, want %q", cfg.ReviewerStrategy, tt.wantStrategy)
+			}
+			if !maps.Equal(cfg.TeamReviewerStrategies, tt.wantTeams) {
+				t.Errorf("TeamReviewerStrategies = %v, want %v", cfg.TeamReviewerStrategies, tt.wantTeams)
+			}
+		})
+	}
+}
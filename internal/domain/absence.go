@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Absence is a scheduled out-of-office period. While it covers the current
+// time the user is skipped by reviewer selection without touching is_active.
+type Absence struct {
+	ID          int64
+	UserID      string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Reason      string
+	CreatedAt   time.Time
+	CancelledAt *time.Time
+}
+
+func (a Absence) Covers(t time.Time) bool {
+	return a.CancelledAt == nil && !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ReviewerAssignment explains why a reviewer was put on a pull request.
+type ReviewerAssignment struct {
+	UserID         string
+	Strategy       string
+	Reason         string
+	FallbackTeam   string
+	CandidatePool  []string
+	ReplacedUserID string
+	AssignedAt     time.Time
+}
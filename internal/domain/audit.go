@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one mutation. Before is empty for creations,
+// After is empty for removals.
+type AuditEntry struct {
+	ID         int64
+	OccurredAt time.Time
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+// AuditFilter selects audit entries, newest first. Empty fields match
+// everything; BeforeID continues a listing after the last entry seen.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   int64
+	Limit      int
+}
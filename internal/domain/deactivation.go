@@ -0,0 +1,61 @@
+package domain
+
+// DeactivationTarget selects the users to deactivate: everyone in TeamName,
+// the users in UserIDs, or with both set the listed users of that team.
+type DeactivationTarget struct {
+	TeamName string
+	UserIDs  []string
+	// AnyTeam lets users outside the authors' teams take over reviews
+	// when the authors' teams themselves have nobody left.
+	AnyTeam bool
+	// LeaveTeam also removes the users from their team.
+	LeaveTeam bool
+}
+
+// OpenReviewSlot is a reviewer seat on an OPEN pull request held by a
+// user who is being deactivated.
+type OpenReviewSlot struct {
+	PullRequestID string
+	Name          string
+	AuthorID      string
+	AuthorTeam    string
+	ReviewerID    string
+	// ReviewerIDs are all reviewers of the pull request, ReviewerID included.
+	ReviewerIDs []string
+}
+
+// ReviewerCandidate is an active, present user who may take over reviews.
+type ReviewerCandidate struct {
+	User        User
+	OpenReviews int
+	// Capacity is the effective limit, nil meaning unlimited.
+	Capacity *int
+}
+
+func (c ReviewerCandidate) AtCapacity() bool {
+	return c.Capacity != nil && c.OpenReviews >= *c.Capacity
+}
+
+// DeactivationSnapshot is what a deactivation is planned against, read
+// in the transaction that applies it.
+type DeactivationSnapshot struct {
+	// Users are the users being deactivated, as they were before.
+	Users      []User
+	Slots      []OpenReviewSlot
+	Candidates []ReviewerCandidate
+	// AuthoredPullRequestIDs are the OPEN and DRAFT pull requests the
+	// users authored. They are left as they are.
+	AuthoredPullRequestIDs []string
+}
+
+// ReviewHandover moves one open review away from a deactivated user.
+// A zero To.UserID leaves the seat unassigned.
+type ReviewHandover struct {
+	PullRequestID string
+	FromUserID    string
+	To            ReviewerAssignment
+}
+
+func (h ReviewHandover) Unassigned() bool {
+	return h.To.UserID == ""
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	EventPrCreated             = "pull_request.created"
+	EventPrReady               = "pull_request.ready"
+	EventPrReviewerReassigned  = "pull_request.reviewer_reassigned"
+	EventPrReviewerUnassigned  = "pull_request.reviewer_unassigned"
+	EventPrReviewSubmitted     = "pull_request.review_submitted"
+	EventPrMerged              = "pull_request.merged"
+	EventPrClosed              = "pull_request.closed"
+	EventPrReopened            = "pull_request.reopened"
+	EventTeamCreated           = "team.created"
+	EventTeamMemberAdded       = "team.member_added"
+	EventTeamMemberUpdated     = "team.member_updated"
+	EventTeamMemberRemoved     = "team.member_removed"
+	EventUserActivationChanged = "user.activation_changed"
+	EventUserTeamChanged       = "user.team_changed"
+)
+
+var EventTypes = []string{
+	EventPrCreated,
+	EventPrReady,
+	EventPrReviewerReassigned,
+	EventPrReviewerUnassigned,
+	EventPrReviewSubmitted,
+	EventPrMerged,
+	EventPrClosed,
+	EventPrReopened,
+	EventTeamCreated,
+	EventTeamMemberAdded,
+	EventTeamMemberUpdated,
+	EventTeamMemberRemoved,
+	EventUserActivationChanged,
+	EventUserTeamChanged,
+}
+
+func ValidEventType(eventType string) bool {
+	return slices.Contains(EventTypes, eventType)
+}
+
+// Event is something that happened to a team, user or pull request,
+// with Data already encoded as JSON.
+type Event struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	Actor      string
+	Data       json.RawMessage
+}
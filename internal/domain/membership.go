@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+const (
+	MembershipTeamCreated = "team_created"
+	MembershipAdded       = "added"
+	MembershipMoved       = "moved"
+)
+
+// TeamMembership is a period a user spent in a team. LeftAt is nil for
+// the team the user is in now.
+type TeamMembership struct {
+	UserID   string
+	TeamName string
+	JoinedAt time.Time
+	LeftAt   *time.Time
+	// Reason tells how the user joined the team.
+	Reason string
+	Actor  string
+}
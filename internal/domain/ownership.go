@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidCodeowners = errors.New("invalid CODEOWNERS content")
+
+// OwnershipRule is a single CODEOWNERS line. Owners are user_ids,
+// optionally prefixed with "@".
+type OwnershipRule struct {
+	Pattern string
+	Owners  []string
+}
+
+func ParseCodeowners(content string) ([]OwnershipRule, error) {
+	var rules []OwnershipRule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Fields(line)
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") {
+			return nil, fmt.Errorf("%w: line %d: unsupported pattern %q", ErrInvalidCodeowners, i+1, pattern)
+		}
+
+		owners := make([]string, 0, len(fields)-1)
+		for _, owner := range fields[1:] {
+			owner = strings.TrimPrefix(owner, "@")
+			if owner == "" {
+				return nil, fmt.Errorf("%w: line %d: empty owner", ErrInvalidCodeowners, i+1)
+			}
+			owners = append(owners, owner)
+		}
+
+		rules = append(rules, OwnershipRule{Pattern: pattern, Owners: owners})
+	}
+	return rules, nil
+}
+
+func FormatCodeowners(rules []OwnershipRule) string {
+	var b strings.Builder
+	for _, rule := range rules {
+		b.WriteString(rule.Pattern)
+		for _, owner := range rule.Owners {
+			b.WriteString(" @")
+			b.WriteString(owner)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// MatchOwners returns the owners of the given paths. As in CODEOWNERS,
+// the last matching rule wins for every path.
+func MatchOwners(rules []OwnershipRule, paths []string) []string {
+	compiled := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		compiled[i] = compilePattern(rule.Pattern)
+	}
+
+	var owners []string
+	for _, path := range paths {
+		path = strings.TrimPrefix(path, "/")
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !compiled[i].MatchString(path) {
+				continue
+			}
+			for _, owner := range rules[i].Owners {
+				if !slices.Contains(owners, owner) {
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// compilePattern converts a gitignore-style CODEOWNERS pattern to a regexp.
+// Patterns containing a slash are anchored at the repository root, others
+// match at any depth. A pattern ending in a slash or in a segment without
+// wildcards names a directory, which owns everything below it; "docs/*"
+// matches the files directly in docs only.
+func compilePattern(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	subtree := directory || !strings.ContainsAny(last, "*?")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	if subtree {
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
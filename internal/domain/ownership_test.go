@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# owners of the service
+*           @alice
+/api/       @bob carol   # inline comment
+
+docs/*.md   @dave
+`
+	rules, err := ParseCodeowners(content)
+	if err != nil {
+		t.Fatalf("ParseCodeowners: %v", err)
+	}
+
+	want := []OwnershipRule{
+		{Pattern: "*", Owners: []string{"alice"}},
+		{Pattern: "/api/", Owners: []string{"bob", "carol"}},
+		{Pattern: "docs/*.md", Owners: []string{"dave"}},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d: %+v", len(rules), len(want), rules)
+	}
+	for i := range want {
+		if rules[i].Pattern != want[i].Pattern || !slices.Equal(rules[i].Owners, want[i].Owners) {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+}
+
+func TestParseCodeownersRejectsUnsupported(t *testing.T) {
+	for _, content := range []string{
+		"!vendor/ @alice",
+		"*.[ch] @alice",
+		"*.go @",
+	} {
+		if _, err := ParseCodeowners(content); !errors.Is(err, ErrInvalidCodeowners) {
+			t.Errorf("ParseCodeowners(%q) error = %v, want ErrInvalidCodeowners", content, err)
+		}
+	}
+}
+
+func TestMatchOwners(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"unanchored file matches at root", "Makefile", "Makefile", true},
+		{"unanchored file matches at any depth", "Makefile", "tools/Makefile", true},
+		{"unanchored directory owns its subtree", "build", "cmd/build/main.go", true},
+		{"anchored directory matches at root", "/api/", "api/handlers.go", true},
+		{"anchored directory owns nested files", "/api/", "api/v1/handlers.go", true},
+		{"anchored directory does not match deeper", "/api/", "internal/api/handlers.go", false},
+		{"path with slash is anchored", "internal/api", "internal/api/x.go", true},
+		{"path with slash does not match deeper", "internal/api", "cmd/internal/api/x.go", false},
+		{"star matches within a segment", "*.go", "internal/domain/team.go", true},
+		{"star suffix does not match a subtree", "*.go", "internal/domain/team.go.orig", false},
+		{"star does not cross a slash", "docs/*", "docs/guide/intro.md", false},
+		{"star matches direct children", "docs/*", "docs/intro.md", true},
+		{"star in a middle segment", "cmd/*/main.go", "cmd/server/main.go", true},
+		{"star in a middle segment stays in one segment", "cmd/*/main.go", "cmd/a/b/main.go", false},
+		{"question mark matches one character", "v?.txt", "v1.txt", true},
+		{"question mark does not match two", "v?.txt", "v10.txt", false},
+		{"question mark does not match a slash", "a?b", "a/b", false},
+		{"leading double star matches at any depth", "**/testdata", "a/b/testdata/x.json", true},
+		{"leading double star matches at root", "**/testdata", "testdata/x.json", true},
+		{"middle double star matches zero directories", "docs/**/*.md", "docs/a.md", true},
+		{"middle double star matches nested directories", "docs/**/*.md", "docs/a/b/c.md", true},
+		{"trailing double star matches everything below", "docs/**", "docs/a/b/c.md", true},
+		{"trailing double star is anchored", "docs/**", "src/docs/a.md", false},
+		{"leading slash on the path is ignored", "/api/", "/api/x.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []OwnershipRule{{Pattern: tt.pattern, Owners: []string{"owner"}}}
+			got := len(MatchOwners(rules, []string{tt.path})) > 0
+			if got != tt.match {
+				t.Errorf("pattern %q, path %q: match = %v, want %v", tt.pattern, tt.path, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestMatchOwnersLastMatchWins(t *testing.T) {
+	rules := []OwnershipRule{
+		{Pattern: "*", Owners: []string{"alice"}},
+		{Pattern: "/api/", Owners: []string{"bob"}},
+		{Pattern: "*.md", Owners: []string{"carol"}},
+		{Pattern: "/api/internal/", Owners: []string{}},
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"catch-all", []string{"main.go"}, []string{"alice"}},
+		{"later directory rule overrides catch-all", []string{"api/handlers.go"}, []string{"bob"}},
+		{"later extension rule overrides directory", []string{"api/README.md"}, []string{"carol"}},
+		{"rule without owners unowns the path", []string{"api/internal/x.go"}, nil},
+		{"owners are collected across paths without duplicates",
+			[]string{"main.go", "api/a.go", "api/b.go", "README.md"}, []string{"alice", "bob", "carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchOwners(rules, tt.paths)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MatchOwners(%v) = %v, want %v", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
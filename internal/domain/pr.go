@@ -2,12 +2,58 @@ package domain
 
 import "time"
 
+type PrStatus string
+
+const (
+	PrStatusDraft  PrStatus = "DRAFT"
+	PrStatusOpen   PrStatus = "OPEN"
+	PrStatusMerged PrStatus = "MERGED"
+	PrStatusClosed PrStatus = "CLOSED"
+)
+
 type PullRequest struct {
 	ID           string
 	Name         string
 	AuthorID     string
-	Status       string
+	Status       PrStatus
 	ReviewersIDs []string
-	CreatedAt    time.Time
-	MergedAt     *time.Time
+	ChangedFiles []string
+	Labels       []string
+	// Reviews holds the latest verdict of each assigned reviewer.
+	Reviews   []Review
+	CreatedAt time.Time
+	MergedAt  *time.Time
+	ClosedAt  *time.Time
+}
+
+// PrSnapshot is a pull request together with its reviewer assignments
+// and the users it refers to, all read at the same moment.
+type PrSnapshot struct {
+	PullRequest PullRequest
+	Assignments []ReviewerAssignment
+	// Users holds the author and the reviewers, keyed by id.
+	Users map[string]User
+}
+
+// PrCursor is the position of the last pull request of a listing page.
+type PrCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// PrFilter selects pull requests, newest first. Empty fields match
+// everything; date ranges include From and exclude To. Team matches the
+// current team of the author, Name matches a case-insensitive substring.
+type PrFilter struct {
+	Statuses    []PrStatus
+	AuthorID    string
+	ReviewerID  string
+	Team        string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	Name        string
+	After       *PrCursor
+	Limit       int
 }
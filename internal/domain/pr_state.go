@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrIllegalTransition = errors.New("illegal pull request status transition")
+
+// prTransitions lists every legal status change. MERGED is final.
+var prTransitions = map[PrStatus][]PrStatus{
+	PrStatusDraft:  {PrStatusOpen},
+	PrStatusOpen:   {PrStatusMerged, PrStatusClosed},
+	PrStatusClosed: {PrStatusOpen},
+}
+
+func (s PrStatus) CanTransitionTo(next PrStatus) bool {
+	for _, allowed := range prTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PrTransition is one recorded status change. From is empty for the
+// initial status a pull request is created with.
+type PrTransition struct {
+	PullRequestID string
+	From          PrStatus
+	To            PrStatus
+	Actor         string
+	Reason        string
+	At            time.Time
+	// AdminOverride marks a merge that skipped the approval policy.
+	AdminOverride bool
+}
+
+func NewPrTransition(pr PullRequest, to PrStatus, actor, reason string, at time.Time) (PrTransition, error) {
+	if !pr.Status.CanTransitionTo(to) {
+		return PrTransition{}, ErrIllegalTransition
+	}
+	return PrTransition{
+		PullRequestID: pr.ID,
+		From:          pr.Status,
+		To:            to,
+		Actor:         actor,
+		Reason:        reason,
+		At:            at,
+	}, nil
+}
+
+// InitialPrTransition records the status a pull request is created with.
+func InitialPrTransition(pr PullRequest, actor string) PrTransition {
+	return PrTransition{
+		PullRequestID: pr.ID,
+		To:            pr.Status,
+		Actor:         actor,
+		Reason:        "created",
+		At:            pr.CreatedAt,
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransitionTo(t *testing.T) {
+	statuses := []PrStatus{PrStatusDraft, PrStatusOpen, PrStatusMerged, PrStatusClosed}
+	legal := map[[2]PrStatus]bool{
+		{PrStatusDraft, PrStatusOpen}:  true,
+		{PrStatusOpen, PrStatusMerged}: true,
+		{PrStatusOpen, PrStatusClosed}: true,
+		{PrStatusClosed, PrStatusOpen}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := legal[[2]PrStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestNewPrTransition(t *testing.T) {
+	at := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		from    PrStatus
+		to      PrStatus
+		wantErr error
+	}{
+		{"draft becomes ready", PrStatusDraft, PrStatusOpen, nil},
+		{"open is merged", PrStatusOpen, PrStatusMerged, nil},
+		{"open is closed", PrStatusOpen, PrStatusClosed, nil},
+		{"closed is reopened", PrStatusClosed, PrStatusOpen, nil},
+		{"draft cannot be merged", PrStatusDraft, PrStatusMerged, ErrIllegalTransition},
+		{"draft cannot be closed", PrStatusDraft, PrStatusClosed, ErrIllegalTransition},
+		{"closed cannot be merged", PrStatusClosed, PrStatusMerged, ErrIllegalTransition},
+		{"merged is final", PrStatusMerged, PrStatusOpen, ErrIllegalTransition},
+		{"open cannot go back to draft", PrStatusOpen, PrStatusDraft, ErrIllegalTransition},
+		{"no transition to the same status", PrStatusOpen, PrStatusOpen, ErrIllegalTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := PullRequest{ID: "pr-1", Status: tt.from}
+			got, err := NewPrTransition(pr, tt.to, "u1", "reason", at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPrTransition error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want := PrTransition{PullRequestID: "pr-1", From: tt.from, To: tt.to, Actor: "u1", Reason: "reason", At: at}
+			if got != want {
+				t.Errorf("NewPrTransition = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
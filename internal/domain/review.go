@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+)
+
+func ValidVerdict(verdict string) bool {
+	return verdict == VerdictApproved || verdict == VerdictChangesRequested
+}
+
+// Review is a verdict submitted by a reviewer. A reviewer may submit
+// several times; only the latest one counts.
+type Review struct {
+	ID            int64
+	PullRequestID string
+	ReviewerID    string
+	Verdict       string
+	Comment       string
+	SubmittedAt   time.Time
+}
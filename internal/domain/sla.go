@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	SLAActionEscalate = "ESCALATE"
+	SLAActionReassign = "REASSIGN"
+)
+
+func ValidSLAAction(action string) bool {
+	return action == SLAActionEscalate || action == SLAActionReassign
+}
+
+const (
+	EscalationEscalated  = "ESCALATED"
+	EscalationReassigned = "REASSIGNED"
+)
+
+// OverdueReview is an assigned reviewer of an OPEN pull request who has
+// not submitted a verdict within the author team's SLA. The SLA clock
+// starts when the reviewer is assigned or the pull request is (re)opened,
+// whichever is later.
+type OverdueReview struct {
+	PullRequestID string
+	ReviewerID    string
+	TeamName      string
+	Action        string
+	StartedAt     time.Time
+	Deadline      time.Time
+}
+
+type Escalation struct {
+	ID            int64
+	PullRequestID string
+	ReviewerID    string
+	Action        string
+	ReplacedBy    string
+	Note          string
+	SLAStartedAt  time.Time
+	CreatedAt     time.Time
+}
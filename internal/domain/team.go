@@ -3,3 +3,109 @@ package domain
 type Team struct {
 	Name string
 }
+
+const (
+	CapacityAssignAnyway = "ASSIGN_ANYWAY"
+	CapacityAssignFewer  = "ASSIGN_FEWER"
+	CapacityReject       = "REJECT"
+)
+
+func ValidCapacityPolicy(policy string) bool {
+	switch policy {
+	case CapacityAssignAnyway, CapacityAssignFewer, CapacityReject:
+		return true
+	}
+	return false
+}
+
+// OnConflict modes decide what creating a team does with requested members
+// who already belong to another team.
+const (
+	OnConflictFail = "fail"
+	OnConflictMove = "move"
+	OnConflictSkip = "skip"
+)
+
+func ValidOnConflict(mode string) bool {
+	switch mode {
+	case OnConflictFail, OnConflictMove, OnConflictSkip:
+		return true
+	}
+	return false
+}
+
+const (
+	ApprovalNone         = "NONE"
+	ApprovalAllApproved  = "ALL_APPROVED"
+	ApprovalMinApprovals = "MIN_APPROVALS"
+)
+
+func ValidApprovalPolicy(policy string) bool {
+	switch policy {
+	case ApprovalNone, ApprovalAllApproved, ApprovalMinApprovals:
+		return true
+	}
+	return false
+}
+
+type TeamSettings struct {
+	TeamName     string
+	MinReviewers int
+	MaxReviewers int
+	// FallbackTeams are consulted in order when the team itself
+	// cannot fill the reviewer slots.
+	FallbackTeams []string
+	// MaxOpenReviews is the default per-member capacity, nil meaning unlimited.
+	MaxOpenReviews *int
+	// CapacityPolicy decides what happens when members at capacity
+	// are the only ones left to fill the reviewer slots.
+	CapacityPolicy string
+	// ApprovalPolicy decides which reviews a pull request needs before
+	// it may be merged; RequiredApprovals applies to MIN_APPROVALS.
+	ApprovalPolicy    string
+	RequiredApprovals int
+	// ReviewSLAMinutes is how long an assigned reviewer may stay without
+	// a verdict, nil meaning no SLA. SLAAction is applied once it is missed.
+	ReviewSLAMinutes *int
+	SLAAction        string
+}
+
+func DefaultTeamSettings(teamName string) TeamSettings {
+	return TeamSettings{
+		TeamName:          teamName,
+		MinReviewers:      1,
+		MaxReviewers:      2,
+		CapacityPolicy:    CapacityAssignFewer,
+		ApprovalPolicy:    ApprovalNone,
+		RequiredApprovals: 1,
+		SLAAction:         SLAActionEscalate,
+	}
+}
+
+// ApprovalSatisfied reports whether the latest reviews of the currently
+// assigned reviewers allow a merge under the team's approval policy.
+func (s TeamSettings) ApprovalSatisfied(reviewerIDs []string, reviews []Review) bool {
+	if s.ApprovalPolicy == ApprovalNone || s.ApprovalPolicy == "" {
+		return true
+	}
+
+	verdicts := make(map[string]string, len(reviews))
+	for _, r := range reviews {
+		verdicts[r.ReviewerID] = r.Verdict
+	}
+
+	approvals := 0
+	for _, id := range reviewerIDs {
+		switch verdicts[id] {
+		case VerdictApproved:
+			approvals++
+		case VerdictChangesRequested:
+			return false
+		}
+	}
+
+	if s.ApprovalPolicy == ApprovalAllApproved {
+		return len(reviewerIDs) > 0 && approvals == len(reviewerIDs)
+	}
+	return approvals >= s.RequiredApprovals
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "testing"
+
+func TestApprovalSatisfied(t *testing.T) {
+	approved := func(id string) Review { return Review{ReviewerID: id, Verdict: VerdictApproved} }
+	changes := func(id string) Review { return Review{ReviewerID: id, Verdict: VerdictChangesRequested} }
+
+	tests := []struct {
+		name      string
+		policy    string
+		required  int
+		reviewers []string
+		reviews   []Review
+		want      bool
+	}{
+		{"none without reviews", ApprovalNone, 1, []string{"u1"}, nil, true},
+		{"none ignores changes requested", ApprovalNone, 1, []string{"u1"}, []Review{changes("u1")}, true},
+		{"empty policy behaves as none", "", 1, []string{"u1"}, nil, true},
+
+		{"all approved", ApprovalAllApproved, 1, []string{"u1", "u2"},
+			[]Review{approved("u1"), approved("u2")}, true},
+		{"all approved with a missing review", ApprovalAllApproved, 1, []string{"u1", "u2"},
+			[]Review{approved("u1")}, false},
+		{"all approved without reviewers", ApprovalAllApproved, 1, nil, nil, false},
+		{"all approved vetoed by changes requested", ApprovalAllApproved, 1, []string{"u1", "u2"},
+			[]Review{approved("u1"), changes("u2")}, false},
+		{"all approved ignores former reviewers", ApprovalAllApproved, 1, []string{"u1"},
+			[]Review{approved("u1"), changes("u9")}, true},
+
+		{"min approvals reached", ApprovalMinApprovals, 2, []string{"u1", "u2", "u3"},
+			[]Review{approved("u1"), approved("u3")}, true},
+		{"min approvals not reached", ApprovalMinApprovals, 2, []string{"u1", "u2", "u3"},
+			[]Review{approved("u1")}, false},
+		{"min approvals above reviewer count", ApprovalMinApprovals, 3, []string{"u1", "u2"},
+			[]Review{approved("u1"), approved("u2")}, false},
+		{"min approvals vetoed by changes requested", ApprovalMinApprovals, 1, []string{"u1", "u2"},
+			[]Review{approved("u1"), changes("u2")}, false},
+		{"min approvals ignores approvals of former reviewers", ApprovalMinApprovals, 2, []string{"u1", "u2"},
+			[]Review{approved("u1"), approved("u9")}, false},
+
+		{"latest approval lifts changes requested", ApprovalMinApprovals, 1, []string{"u1"},
+			[]Review{changes("u1"), approved("u1")}, true},
+		{"latest changes requested withdraws approval", ApprovalAllApproved, 1, []string{"u1"},
+			[]Review{approved("u1"), changes("u1")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := TeamSettings{ApprovalPolicy: tt.policy, RequiredApprovals: tt.required}
+			if got := s.ApprovalSatisfied(tt.reviewers, tt.reviews); got != tt.want {
+				t.Errorf("ApprovalSatisfied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,49 @@
 package domain
 
+import (
+	"slices"
+	"strings"
+)
+
 type User struct {
 	ID       string
 	Username string
 	IsActive bool
+	// TeamName is empty for a user who was removed from their team.
 	TeamName string
+	Tags     []string
+	// MaxOpenReviews overrides the team default capacity when set.
+	MaxOpenReviews *int
+}
+
+// Capacity returns the effective limit of concurrently open reviews,
+// nil meaning unlimited.
+func (u User) Capacity(teamDefault *int) *int {
+	if u.MaxOpenReviews != nil {
+		return u.MaxOpenReviews
+	}
+	return teamDefault
+}
+
+func (u User) HasAnyTag(tags []string) bool {
+	for _, t := range u.Tags {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags lowercases, trims and deduplicates tags and PR labels
+// so that "DB" and " db" match each other.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized
 }
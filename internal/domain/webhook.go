@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryFailed    = "FAILED"
+)
+
+type WebhookSubscription struct {
+	ID     int64
+	URL    string
+	Secret string
+	// EventTypes limits the subscription to these events, empty meaning all.
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery is one event queued for one subscription. Payload is
+// the exact request body, so that redeliveries are byte for byte the same.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      string
+	DeliveredAt    *time.Time
+	RedeliveryOf   *int64
+	CreatedAt      time.Time
+
+	// URL and Secret come from the subscription when a delivery is claimed.
+	URL    string
+	Secret string
+}
+
+type DeliveryFilter struct {
+	SubscriptionID int64
+	Status         string
+	// BeforeID continues a listing after the last delivery of a previous page.
+	BeforeID int64
+	Limit    int
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"PR_project/internal/domain"
+	"PR_project/internal/service"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// insertAudit records changes made in tx, so that the audit entries
+// commit or roll back together with the change they describe.
+func insertAudit(ctx context.Context, tx *sql.Tx, action, entityType string, changes ...service.AuditChange) error {
+	entries, err := service.NewAuditEntries(ctx, action, entityType, changes...)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	type auditRow struct {
+		OccurredAt time.Time       `json:"occurred_at"`
+		Actor      string          `json:"actor"`
+		Action     string          `json:"action"`
+		EntityType string          `json:"entity_type"`
+		EntityID   string          `json:"entity_id"`
+		Before     json.RawMessage `json:"before"`
+		After      json.RawMessage `json:"after"`
+	}
+	rows := make([]auditRow, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, auditRow{
+			OccurredAt: e.OccurredAt,
+			Actor:      e.Actor,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Before:     rawOrNull(e.Before),
+			After:      rawOrNull(e.After),
+		})
+	}
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO audit_log (occurred_at, actor, action, entity_type, entity_id, before, after)
+SELECT e.occurred_at, NULLIF(e.actor, ''), e.action, e.entity_type, e.entity_id, e.before, e.after
+FROM jsonb_to_recordset($1::jsonb)
+    AS e(occurred_at timestamptz, actor text, action text, entity_type text, entity_id text, before jsonb, after jsonb);`
+	_, err = tx.ExecContext(ctx, query, string(payload))
+	return err
+}
+
+func (r *PostgresAuditRepository) ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	query := `SELECT audit_id, occurred_at, COALESCE(actor, ''), action, entity_type, entity_id, before, after
+FROM audit_log
+WHERE ($1 = '' OR actor = $1)
+  AND ($2 = '' OR action = $2)
+  AND ($3 = '' OR entity_type = $3)
+  AND ($4 = '' OR entity_id = $4)
+  AND ($5::timestamptz IS NULL OR occurred_at >= $5)
+  AND ($6::timestamptz IS NULL OR occurred_at < $6)
+  AND ($7 = 0 OR audit_id < $7)
+ORDER BY audit_id DESC
+LIMIT $8;`
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		filter.Actor,
+		filter.Action,
+		filter.EntityType,
+		filter.EntityID,
+		filter.From,
+		filter.To,
+		filter.BeforeID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var e domain.AuditEntry
+		var before, after []byte
+		err = rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &before, &after)
+		if err != nil {
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// rawOrNull encodes an empty payload as JSON null, which is stored as SQL NULL.
+func rawOrNull(payload json.RawMessage) json.RawMessage {
+	if len(payload) == 0 {
+		return json.RawMessage("null")
+	}
+	return payload
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"PR_project/internal/domain"
+	"PR_project/internal/service"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// ClaimEvents returns up to limit unpublished events that are due, oldest
+// first, and leases them so that another dispatcher does not publish them
+// concurrently. An event that is not marked published before the lease
+// ends is claimed again.
+func (r *PostgresOutboxRepository) ClaimEvents(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]domain.Event, error) {
+	query := `WITH due AS (
+    SELECT event_id
+    FROM outbox
+    WHERE published_at IS NULL
+      AND next_attempt_at <= $1
+    ORDER BY occurred_at, event_id
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox o
+SET next_attempt_at = $2,
+    attempts = o.attempts + 1
+FROM due
+WHERE o.event_id = due.event_id
+RETURNING o.event_id, o.event_type, COALESCE(o.actor, ''), o.data, o.occurred_at;`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var e domain.Event
+		var data []byte
+		err = rows.Scan(&e.ID, &e.Type, &e.Actor, &data, &e.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		e.Data = data
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the CTE.
+	slices.SortFunc(events, func(a, b domain.Event) int {
+		if c := a.OccurredAt.Compare(b.OccurredAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return events, nil
+}
+
+func (r *PostgresOutboxRepository) MarkPublished(ctx context.Context, eventID string, publishedAt time.Time) error {
+	query := `UPDATE outbox SET published_at = $2, last_error = '' WHERE event_id = $1;`
+	_, err := r.db.ExecContext(ctx, query, eventID, publishedAt)
+	return err
+}
+
+// MarkFailed records why publishing failed; the event is retried once
+// its lease runs out.
+func (r *PostgresOutboxRepository) MarkFailed(ctx context.Context, eventID string, reason string) error {
+	query := `UPDATE outbox SET last_error = $2 WHERE event_id = $1;`
+	_, err := r.db.ExecContext(ctx, query, eventID, reason)
+	return err
+}
+
+// insertEvent writes an event to the outbox as part of tx, so that it is
+// published if and only if tx commits.
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType string, data any) error {
+	event, err := service.NewEvent(ctx, eventType, data)
+	if err != nil {
+		return err
+	}
+	return insertEvents(ctx, tx, []domain.Event{event})
+}
+
+// insertEvents is insertEvent for many events, written in one statement.
+func insertEvents(ctx context.Context, tx *sql.Tx, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	type outboxRow struct {
+		EventID    string          `json:"event_id"`
+		EventType  string          `json:"event_type"`
+		Actor      string          `json:"actor"`
+		Data       json.RawMessage `json:"data"`
+		OccurredAt time.Time       `json:"occurred_at"`
+	}
+	rows := make([]outboxRow, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, outboxRow{
+			EventID:    e.ID,
+			EventType:  e.Type,
+			Actor:      e.Actor,
+			Data:       e.Data,
+			OccurredAt: e.OccurredAt,
+		})
+	}
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox (event_id, event_type, actor, data, occurred_at)
+SELECT e.event_id, e.event_type, NULLIF(e.actor, ''), e.data, e.occurred_at
+FROM jsonb_to_recordset($1::jsonb)
+    AS e(event_id text, event_type text, actor text, data jsonb, occurred_at timestamptz);`
+	_, err = tx.ExecContext(ctx, query, string(payload))
+	return err
+}
+
+// prEventData reads the pull request and its reviewers as seen by tx.
+func prEventData(ctx context.Context, tx *sql.Tx, prID string) (service.PrEventData, error) {
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+       ARRAY(SELECT rev.user_id
+             FROM pr_reviewers rev
+             WHERE rev.pull_request_id = pr.pull_request_id
+             ORDER BY rev.user_id)
+FROM pull_requests pr
+WHERE pr.pull_request_id = $1;`
+	var d service.PrEventData
+	err := tx.QueryRowContext(ctx, query, prID).
+		Scan(&d.PullRequestID, &d.Name, &d.AuthorID, &d.Status, (*pq.StringArray)(&d.ReviewerIDs))
+	if err != nil {
+		return service.PrEventData{}, err
+	}
+	if d.ReviewerIDs == nil {
+		d.ReviewerIDs = []string{}
+	}
+	return d, nil
+}
+
+func insertPrEvent(ctx context.Context, tx *sql.Tx, eventType, prID string) error {
+	data, err := prEventData(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+	return insertEvent(ctx, tx, eventType, data)
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"PR_project/internal/domain"
+	"PR_project/internal/service"
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type PostgresPrRepository struct {
@@ -33,7 +37,8 @@ func (r *PostgresPrRepository) PRExists(ctx context.Context, prID string) (bool,
 func (r *PostgresPrRepository) CreatePRWithReviewers(
 	ctx context.Context,
 	pr domain.PullRequest,
-	reviewerIDs []string,
+	reviewers []domain.ReviewerAssignment,
+	created domain.PrTransition,
 ) (domain.PullRequest, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -41,26 +46,51 @@ func (r *PostgresPrRepository) CreatePRWithReviewers(
 	}
 	defer tx.Rollback()
 
-	insertPrQuery := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = tx.ExecContext(ctx, insertPrQuery, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt)
+	insertPrQuery := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, changed_files, labels)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(
+		ctx,
+		insertPrQuery,
+		pr.ID,
+		pr.Name,
+		pr.AuthorID,
+		pr.Status,
+		pr.CreatedAt,
+		pr.MergedAt,
+		pq.Array(pr.ChangedFiles),
+		pq.Array(pr.Labels),
+	)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
 
-	insertReviewersQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id)
-VALUES ($1, $2)`
+	err = insertTransition(ctx, tx, created)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
 
-	for _, rev := range reviewerIDs {
-		_, err = tx.ExecContext(
-			ctx,
-			insertReviewersQuery,
-			pr.ID,
-			rev,
-		)
+	var reviewerIDs []string
+	for _, rev := range reviewers {
+		err = insertReviewer(ctx, tx, pr.ID, rev)
 		if err != nil {
 			return domain.PullRequest{}, err
 		}
+		reviewerIDs = append(reviewerIDs, rev.UserID)
+	}
+
+	err = insertPrEvent(ctx, tx, domain.EventPrCreated, pr.ID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	stored, err := getPRWithReviewers(ctx, tx, pr.ID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	err = insertAudit(ctx, tx, service.AuditPrCreate, service.AuditEntityPullRequest,
+		service.AuditChange{EntityID: pr.ID, After: stored})
+	if err != nil {
+		return domain.PullRequest{}, err
 	}
 
 	prUpdated := domain.PullRequest{
@@ -71,6 +101,8 @@ VALUES ($1, $2)`
 		CreatedAt:    pr.CreatedAt,
 		MergedAt:     pr.MergedAt,
 		ReviewersIDs: reviewerIDs,
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       pr.Labels,
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -81,14 +113,28 @@ VALUES ($1, $2)`
 }
 
 func (r *PostgresPrRepository) GetPRWithReviewers(ctx context.Context, prID string) (domain.PullRequest, error) {
-	selectPrsQuery := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at 
+	return getPRWithReviewers(ctx, r.db, prID)
+}
+
+func getPRWithReviewers(ctx context.Context, q queryer, prID string) (domain.PullRequest, error) {
+	selectPrsQuery := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, changed_files, labels
 FROM pull_requests
 WHERE pull_request_id = $1`
-	row := r.db.QueryRowContext(ctx, selectPrsQuery, prID)
+	row := q.QueryRowContext(ctx, selectPrsQuery, prID)
 
 	var pr domain.PullRequest
 
-	err := row.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	err := row.Scan(
+		&pr.ID,
+		&pr.Name,
+		&pr.AuthorID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+		(*pq.StringArray)(&pr.ChangedFiles),
+		(*pq.StringArray)(&pr.Labels),
+	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return domain.PullRequest{}, err
 	}
@@ -99,7 +145,7 @@ WHERE pull_request_id = $1`
 	selectReviewersQuery := `SELECT user_id 
 FROM pr_reviewers 
 WHERE pull_request_id = $1`
-	revRows, err := r.db.QueryContext(ctx, selectReviewersQuery, prID)
+	revRows, err := q.QueryContext(ctx, selectReviewersQuery, prID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -120,6 +166,11 @@ WHERE pull_request_id = $1`
 		return domain.PullRequest{}, err
 	}
 
+	reviews, err := getLatestReviews(ctx, q, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
 	pullRequest := domain.PullRequest{
 		ID:           pr.ID,
 		Name:         pr.Name,
@@ -127,31 +178,407 @@ WHERE pull_request_id = $1`
 		Status:       pr.Status,
 		CreatedAt:    pr.CreatedAt,
 		MergedAt:     pr.MergedAt,
+		ClosedAt:     pr.ClosedAt,
 		ReviewersIDs: reviewerIDs,
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       pr.Labels,
+		Reviews:      reviews,
 	}
 
 	return pullRequest, nil
 }
 
-func (r *PostgresPrRepository) MarkMerged(ctx context.Context, prID string, mergedAt time.Time) error {
-	updatePrsQuery := `UPDATE pull_requests 
-SET status = 'MERGED',
-    merged_at = $2
-WHERE pull_request_id = $1`
-	_, err := r.db.ExecContext(ctx, updatePrsQuery, prID, mergedAt)
+// likeEscaper escapes a user string for use inside a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListPRs returns one page of pull requests matching the filter, ordered
+// by creation time and id, newest first. Reviews are not loaded.
+func (r *PostgresPrRepository) ListPRs(ctx context.Context, filter domain.PrFilter) ([]domain.PullRequest, error) {
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+       pr.closed_at, pr.changed_files, pr.labels,
+       ARRAY(SELECT rev.user_id
+             FROM pr_reviewers rev
+             WHERE rev.pull_request_id = pr.pull_request_id
+             ORDER BY rev.user_id)
+FROM pull_requests pr
+JOIN users author
+    ON author.user_id = pr.author_id
+WHERE (cardinality($1::text[]) = 0 OR pr.status = ANY($1::text[]))
+  AND ($2 = '' OR pr.author_id = $2)
+  AND ($3 = '' OR EXISTS (SELECT 1
+                          FROM pr_reviewers rev
+                          WHERE rev.pull_request_id = pr.pull_request_id
+                            AND rev.user_id = $3))
+  AND ($4 = '' OR author.team_name = $4)
+  AND ($5::timestamptz IS NULL OR pr.created_at >= $5)
+  AND ($6::timestamptz IS NULL OR pr.created_at < $6)
+  AND ($7::timestamptz IS NULL OR pr.merged_at >= $7)
+  AND ($8::timestamptz IS NULL OR pr.merged_at < $8)
+  AND ($9 = '' OR pr.pull_request_name ILIKE '%' || $9 || '%')
+  AND ($10::timestamptz IS NULL OR (pr.created_at, pr.pull_request_id) < ($10, $11::text))
+ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+LIMIT $12;`
+
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, st := range filter.Statuses {
+		statuses = append(statuses, string(st))
+	}
+	var afterAt *time.Time
+	var afterID string
+	if filter.After != nil {
+		afterAt = &filter.After.CreatedAt
+		afterID = filter.After.ID
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		pq.Array(statuses),
+		filter.AuthorID,
+		filter.ReviewerID,
+		filter.Team,
+		filter.CreatedFrom,
+		filter.CreatedTo,
+		filter.MergedFrom,
+		filter.MergedTo,
+		likeEscaper.Replace(filter.Name),
+		afterAt,
+		afterID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
+		err = rows.Scan(
+			&pr.ID,
+			&pr.Name,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+			(*pq.StringArray)(&pr.ChangedFiles),
+			(*pq.StringArray)(&pr.Labels),
+			(*pq.StringArray)(&pr.ReviewersIDs),
+		)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// getLatestReviews returns the most recent verdict of every reviewer
+// currently assigned to the pull request.
+func getLatestReviews(ctx context.Context, q queryer, prID string) ([]domain.Review, error) {
+	query := `SELECT DISTINCT ON (rv.user_id)
+       rv.review_id, rv.pull_request_id, rv.user_id, rv.verdict, rv.comment, rv.submitted_at
+FROM pr_reviews rv
+JOIN pr_reviewers rev
+    ON rev.pull_request_id = rv.pull_request_id
+   AND rev.user_id = rv.user_id
+WHERE rv.pull_request_id = $1
+ORDER BY rv.user_id, rv.submitted_at DESC, rv.review_id DESC;`
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.Review
+	for rows.Next() {
+		var rv domain.Review
+		err = rows.Scan(&rv.ID, &rv.PullRequestID, &rv.ReviewerID, &rv.Verdict, &rv.Comment, &rv.SubmittedAt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// lockPR locks the pull request row for the rest of tx and returns the
+// pull request as it is before the change. It returns sql.ErrNoRows when
+// the pull request does not exist.
+func lockPR(ctx context.Context, tx *sql.Tx, prID string) (domain.PullRequest, error) {
+	query := `SELECT pull_request_id FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE;`
+	if err := tx.QueryRowContext(ctx, query, prID).Scan(&prID); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return getPRWithReviewers(ctx, tx, prID)
+}
+
+// insertPrAudit records a change of the pull request, reading its new
+// state through tx.
+func insertPrAudit(ctx context.Context, tx *sql.Tx, action string, before domain.PullRequest) error {
+	after, err := getPRWithReviewers(ctx, tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return insertAudit(ctx, tx, action, service.AuditEntityPullRequest,
+		service.AuditChange{EntityID: before.ID, Before: before, After: after})
+}
+
+func (r *PostgresPrRepository) SubmitReview(ctx context.Context, review domain.Review) (domain.Review, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO pr_reviews (pull_request_id, user_id, verdict, comment, submitted_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING review_id, pull_request_id, user_id, verdict, comment, submitted_at;`
+	row := tx.QueryRowContext(
+		ctx,
+		query,
+		review.PullRequestID,
+		review.ReviewerID,
+		review.Verdict,
+		review.Comment,
+		review.SubmittedAt,
+	)
+
+	var rv domain.Review
+	err = row.Scan(&rv.ID, &rv.PullRequestID, &rv.ReviewerID, &rv.Verdict, &rv.Comment, &rv.SubmittedAt)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	data, err := prEventData(ctx, tx, rv.PullRequestID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	err = insertEvent(ctx, tx, domain.EventPrReviewSubmitted, service.ReviewSubmittedData{
+		PrEventData: data,
+		ReviewerID:  rv.ReviewerID,
+		Verdict:     rv.Verdict,
+		Comment:     rv.Comment,
+	})
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditPrReview, service.AuditEntityPullRequest,
+		service.AuditChange{EntityID: rv.PullRequestID, After: rv})
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Review{}, err
+	}
+
+	return rv, nil
+}
+
+// ApplyTransition changes the status of a pull request and records the
+// change in its history. It returns sql.ErrNoRows when the pull request
+// is no longer in t.From.
+func (r *PostgresPrRepository) ApplyTransition(ctx context.Context, t domain.PrTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	before, err := lockPR(ctx, tx, t.PullRequestID)
+	if err != nil {
+		return err
+	}
+
+	err = applyTransition(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	err = insertPrEvent(ctx, tx, transitionEventType(t.To), t.PullRequestID)
+	if err != nil {
+		return err
+	}
+
+	err = insertPrAudit(ctx, tx, transitionAuditAction(t), before)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (r *PostgresPrRepository) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
+// MarkReady applies the DRAFT to OPEN transition and assigns the
+// reviewers in one transaction.
+func (r *PostgresPrRepository) MarkReady(
+	ctx context.Context,
+	t domain.PrTransition,
+	reviewers []domain.ReviewerAssignment,
+) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	before, err := lockPR(ctx, tx, t.PullRequestID)
+	if err != nil {
+		return err
+	}
+
+	err = applyTransition(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range reviewers {
+		err = insertReviewer(ctx, tx, t.PullRequestID, rev)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertPrEvent(ctx, tx, domain.EventPrReady, t.PullRequestID)
+	if err != nil {
+		return err
+	}
+
+	err = insertPrAudit(ctx, tx, service.AuditPrReady, before)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func applyTransition(ctx context.Context, tx *sql.Tx, t domain.PrTransition) error {
+	updatePrsQuery := `UPDATE pull_requests
+SET status = $3,
+    merged_at = CASE WHEN $3 = 'MERGED' THEN $4 ELSE merged_at END,
+    closed_at = CASE WHEN $3 = 'CLOSED' THEN $4 WHEN $3 = 'OPEN' THEN NULL ELSE closed_at END
+WHERE pull_request_id = $1
+  AND status = $2`
+	res, err := tx.ExecContext(ctx, updatePrsQuery, t.PullRequestID, t.From, t.To, t.At)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return insertTransition(ctx, tx, t)
+}
+
+// transitionAuditAction names the audit action of the transition. Merges
+// that skipped the approval policy get an action of their own, so that
+// they stand out in the log.
+func transitionAuditAction(t domain.PrTransition) string {
+	switch t.To {
+	case domain.PrStatusMerged:
+		if t.AdminOverride {
+			return service.AuditPrMergeOverride
+		}
+		return service.AuditPrMerge
+	case domain.PrStatusClosed:
+		return service.AuditPrClose
+	}
+	return service.AuditPrReopen
+}
+
+// transitionEventType names the event published when a pull request
+// reaches the given status.
+func transitionEventType(to domain.PrStatus) string {
+	switch to {
+	case domain.PrStatusMerged:
+		return domain.EventPrMerged
+	case domain.PrStatusClosed:
+		return domain.EventPrClosed
+	}
+	return domain.EventPrReopened
+}
+
+func insertTransition(ctx context.Context, tx *sql.Tx, t domain.PrTransition) error {
+	insertHistoryQuery := `INSERT INTO pr_status_history (pull_request_id, from_status, to_status, actor, reason, changed_at)
+VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, ''), $5, $6);`
+	_, err := tx.ExecContext(ctx, insertHistoryQuery, t.PullRequestID, t.From, t.To, t.Actor, t.Reason, t.At)
+	return err
+}
+
+func (r *PostgresPrRepository) GetStatusHistory(ctx context.Context, prID string) ([]domain.PrTransition, error) {
+	query := `SELECT pull_request_id, COALESCE(from_status, ''), to_status, COALESCE(actor, ''), reason, changed_at
+FROM pr_status_history
+WHERE pull_request_id = $1
+ORDER BY changed_at, history_id;`
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []domain.PrTransition
+	for rows.Next() {
+		var t domain.PrTransition
+		err = rows.Scan(&t.PullRequestID, &t.From, &t.To, &t.Actor, &t.Reason, &t.At)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// ReplaceReviewer locks the pull request, passes it to check and, unless
+// check fails, swaps oldUserID for the new reviewer. It returns
+// sql.ErrNoRows when the pull request does not exist.
+func (r *PostgresPrRepository) ReplaceReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+	newReviewer domain.ReviewerAssignment,
+	check func(ctx context.Context, current domain.PullRequest) error,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockPR(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+
+	err = check(ctx, before)
+	if err != nil {
+		return err
+	}
+
 	deleteOldQuery := `DELETE FROM pr_reviewers
 WHERE pull_request_id = $1 AND user_id = $2;`
 	_, err = tx.ExecContext(ctx, deleteOldQuery, prID, oldUserID)
@@ -159,9 +586,30 @@ WHERE pull_request_id = $1 AND user_id = $2;`
 		return err
 	}
 
-	insertNewQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id)
-VALUES ($1, $2);`
-	_, err = tx.ExecContext(ctx, insertNewQuery, prID, newUserID)
+	err = retireRationale(ctx, tx, []string{prID}, []string{oldUserID})
+	if err != nil {
+		return err
+	}
+
+	err = insertReviewer(ctx, tx, prID, newReviewer)
+	if err != nil {
+		return err
+	}
+
+	data, err := prEventData(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+	err = insertEvent(ctx, tx, domain.EventPrReviewerReassigned, service.ReviewerReassignedData{
+		PrEventData:   data,
+		OldReviewerID: oldUserID,
+		NewReviewerID: newReviewer.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = insertPrAudit(ctx, tx, service.AuditPrReassign, before)
 	if err != nil {
 		return err
 	}
@@ -172,3 +620,157 @@ VALUES ($1, $2);`
 
 	return nil
 }
+
+func insertReviewer(ctx context.Context, tx *sql.Tx, prID string, rev domain.ReviewerAssignment) error {
+	insertReviewerQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id)
+VALUES ($1, $2);`
+	_, err := tx.ExecContext(ctx, insertReviewerQuery, prID, rev.UserID)
+	if err != nil {
+		return err
+	}
+
+	insertRationaleQuery := `INSERT INTO pr_reviewer_rationale
+    (pull_request_id, user_id, strategy, reason, fallback_team, candidate_pool, replaced_user_id, assigned_at)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), $8);`
+	_, err = tx.ExecContext(
+		ctx,
+		insertRationaleQuery,
+		prID,
+		rev.UserID,
+		rev.Strategy,
+		rev.Reason,
+		rev.FallbackTeam,
+		pq.Array(rev.CandidatePool),
+		rev.ReplacedUserID,
+		rev.AssignedAt,
+	)
+	return err
+}
+
+// retireRationale marks the current rationale of removed reviewers as
+// replaced; the rows stay as assignment history.
+func retireRationale(ctx context.Context, tx *sql.Tx, prIDs, userIDs []string) error {
+	query := `UPDATE pr_reviewer_rationale rat
+SET replaced_at = NOW()
+FROM unnest($1::text[], $2::text[]) AS r(pull_request_id, user_id)
+WHERE rat.pull_request_id = r.pull_request_id
+  AND rat.user_id = r.user_id
+  AND rat.replaced_at IS NULL;`
+	_, err := tx.ExecContext(ctx, query, pq.Array(prIDs), pq.Array(userIDs))
+	return err
+}
+
+func (r *PostgresPrRepository) GetReviewerAssignments(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error) {
+	return getReviewerAssignments(ctx, r.db, prID)
+}
+
+// GetPRSnapshot reads the pull request, its reviewer assignments and the
+// users they refer to in one read-only REPEATABLE READ transaction, so
+// that a concurrent reassignment cannot leave them inconsistent. It
+// returns sql.ErrNoRows when the pull request does not exist.
+func (r *PostgresPrRepository) GetPRSnapshot(ctx context.Context, prID string) (domain.PrSnapshot, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return domain.PrSnapshot{}, err
+	}
+	defer tx.Rollback()
+
+	pr, err := getPRWithReviewers(ctx, tx, prID)
+	if err != nil {
+		return domain.PrSnapshot{}, err
+	}
+
+	assignments, err := getReviewerAssignments(ctx, tx, prID)
+	if err != nil {
+		return domain.PrSnapshot{}, err
+	}
+
+	userIDs := []string{pr.AuthorID}
+	for _, a := range assignments {
+		userIDs = append(userIDs, a.UserID)
+	}
+	users, err := getUsersByIDs(ctx, tx, userIDs)
+	if err != nil {
+		return domain.PrSnapshot{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.PrSnapshot{}, err
+	}
+
+	return domain.PrSnapshot{PullRequest: pr, Assignments: assignments, Users: users}, nil
+}
+
+func getReviewerAssignments(ctx context.Context, q queryer, prID string) ([]domain.ReviewerAssignment, error) {
+	query := `SELECT rev.user_id,
+       COALESCE(rat.strategy, ''),
+       COALESCE(rat.reason, ''),
+       COALESCE(rat.fallback_team, ''),
+       COALESCE(rat.candidate_pool, '{}'),
+       COALESCE(rat.replaced_user_id, ''),
+       rat.assigned_at
+FROM pr_reviewers rev
+LEFT JOIN pr_reviewer_rationale rat
+    ON rat.pull_request_id = rev.pull_request_id
+   AND rat.user_id = rev.user_id
+   AND rat.replaced_at IS NULL
+WHERE rev.pull_request_id = $1
+ORDER BY rat.assigned_at NULLS FIRST, rev.user_id;`
+	rows, err := q.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []domain.ReviewerAssignment
+	for rows.Next() {
+		var a domain.ReviewerAssignment
+		var assignedAt sql.NullTime
+		err = rows.Scan(
+			&a.UserID,
+			&a.Strategy,
+			&a.Reason,
+			&a.FallbackTeam,
+			(*pq.StringArray)(&a.CandidatePool),
+			&a.ReplacedUserID,
+			&assignedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		a.AssignedAt = assignedAt.Time
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+func (r *PostgresPrRepository) GetEscalations(ctx context.Context, prID string) ([]domain.Escalation, error) {
+	query := `SELECT escalation_id, pull_request_id, user_id, action, COALESCE(replaced_by, ''), note, sla_started_at, created_at
+FROM pr_escalations
+WHERE pull_request_id = $1
+ORDER BY created_at, escalation_id;`
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escalations []domain.Escalation
+	for rows.Next() {
+		var e domain.Escalation
+		err = rows.Scan(&e.ID, &e.PullRequestID, &e.ReviewerID, &e.Action, &e.ReplacedBy, &e.Note, &e.SLAStartedAt, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		escalations = append(escalations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return escalations, nil
+}
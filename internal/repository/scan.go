@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// withExtra lets a scan helper read its columns followed by extra ones.
+func withExtra(row rowScanner, extra ...any) rowScanner {
+	return extraScanner{row: row, extra: extra}
+}
+
+type extraScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (s extraScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
+
+// scanUser reads the columns
+// user_id, username, team_name, is_active, tags, max_open_reviews.
+func scanUser(row rowScanner) (domain.User, error) {
+	var u domain.User
+	var teamName sql.NullString
+	var maxOpenReviews sql.NullInt64
+	err := row.Scan(&u.ID, &u.Username, &teamName, &u.IsActive, (*pq.StringArray)(&u.Tags), &maxOpenReviews)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.TeamName = teamName.String
+	if maxOpenReviews.Valid {
+		v := int(maxOpenReviews.Int64)
+		u.MaxOpenReviews = &v
+	}
+	return u, nil
+}
+
+// scanTeamSettings reads the columns
+// team_name, min_reviewers, max_reviewers, max_open_reviews, capacity_policy,
+// approval_policy, required_approvals, review_sla_minutes, sla_action.
+func scanTeamSettings(row rowScanner) (domain.TeamSettings, error) {
+	var s domain.TeamSettings
+	var maxOpenReviews, reviewSLA sql.NullInt64
+	err := row.Scan(&s.TeamName, &s.MinReviewers, &s.MaxReviewers, &maxOpenReviews, &s.CapacityPolicy,
+		&s.ApprovalPolicy, &s.RequiredApprovals, &reviewSLA, &s.SLAAction)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if maxOpenReviews.Valid {
+		v := int(maxOpenReviews.Int64)
+		s.MaxOpenReviews = &v
+	}
+	if reviewSLA.Valid {
+		v := int(reviewSLA.Int64)
+		s.ReviewSLAMinutes = &v
+	}
+	return s, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"PR_project/internal/domain"
+	"PR_project/internal/service"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// slaScanLockKey identifies the advisory lock that lets only one
+// replica scan for overdue reviews at a time.
+const slaScanLockKey = 727001
+
+type PostgresSLARepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSLARepository(db *sql.DB) *PostgresSLARepository {
+	return &PostgresSLARepository{db: db}
+}
+
+// WithScanLock runs fn while holding the scan advisory lock. It reports
+// false without calling fn when another replica holds the lock.
+func (r *PostgresSLARepository) WithScanLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1);`, slaScanLockKey).Scan(&locked)
+	if err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	// The lock is tied to this session, release it even if ctx is done.
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, slaScanLockKey)
+	}()
+
+	return true, fn(ctx)
+}
+
+func (r *PostgresSLARepository) FindOverdueReviews(ctx context.Context, now time.Time, limit int) ([]domain.OverdueReview, error) {
+	query := `WITH clock AS (
+    SELECT rev.pull_request_id,
+           rev.user_id,
+           author.team_name,
+           ts.sla_action,
+           ts.review_sla_minutes,
+           GREATEST(
+               COALESCE(rat.assigned_at, pr.created_at),
+               COALESCE(
+                   (SELECT MAX(h.changed_at)
+                    FROM pr_status_history h
+                    WHERE h.pull_request_id = pr.pull_request_id
+                      AND h.to_status = 'OPEN'),
+                   pr.created_at
+               )
+           ) AS started_at
+    FROM pr_reviewers rev
+    JOIN pull_requests pr
+        ON pr.pull_request_id = rev.pull_request_id
+       AND pr.status = 'OPEN'
+    JOIN users author
+        ON author.user_id = pr.author_id
+    JOIN team_settings ts
+        ON ts.team_name = author.team_name
+       AND ts.review_sla_minutes IS NOT NULL
+    LEFT JOIN pr_reviewer_rationale rat
+        ON rat.pull_request_id = rev.pull_request_id
+       AND rat.user_id = rev.user_id
+       AND rat.replaced_at IS NULL
+    WHERE NOT EXISTS (
+        SELECT 1
+        FROM pr_reviews rv
+        WHERE rv.pull_request_id = rev.pull_request_id
+          AND rv.user_id = rev.user_id
+    )
+)
+SELECT c.pull_request_id, c.user_id, c.team_name, c.sla_action, c.started_at,
+       c.started_at + make_interval(mins => c.review_sla_minutes) AS deadline
+FROM clock c
+WHERE c.started_at + make_interval(mins => c.review_sla_minutes) <= $1
+  AND NOT EXISTS (
+      SELECT 1
+      FROM pr_escalations e
+      WHERE e.pull_request_id = c.pull_request_id
+        AND e.user_id = c.user_id
+        AND e.sla_started_at = c.started_at
+  )
+ORDER BY deadline
+LIMIT $2;`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overdue []domain.OverdueReview
+	for rows.Next() {
+		var o domain.OverdueReview
+		err = rows.Scan(&o.PullRequestID, &o.ReviewerID, &o.TeamName, &o.Action, &o.StartedAt, &o.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overdue, nil
+}
+
+// RecordEscalation stores the escalation unless one already exists for
+// the same reviewer assignment, reporting whether it was stored. A stored
+// escalation without a reassignment is audited; a reassignment is audited
+// by the reassign itself.
+func (r *PostgresSLARepository) RecordEscalation(ctx context.Context, e domain.Escalation) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO pr_escalations (pull_request_id, user_id, action, replaced_by, note, sla_started_at, created_at)
+VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+ON CONFLICT ON CONSTRAINT uq_escalation_assignment DO NOTHING;`
+	res, err := tx.ExecContext(
+		ctx,
+		query,
+		e.PullRequestID,
+		e.ReviewerID,
+		e.Action,
+		e.ReplacedBy,
+		e.Note,
+		e.SLAStartedAt,
+		e.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if n == 1 && e.Action == domain.EscalationEscalated {
+		err = insertAudit(ctx, tx, service.AuditPrEscalate, service.AuditEntityPullRequest,
+			service.AuditChange{EntityID: e.PullRequestID, After: e})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
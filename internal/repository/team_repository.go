@@ -6,6 +6,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
+
+	"github.com/lib/pq"
 )
 
 type PostgresTeamRepository struct {
@@ -33,7 +36,7 @@ func (r *PostgresTeamRepository) TeamExists(ctx context.Context, teamName string
 func (r *PostgresTeamRepository) GetTeam(ctx context.Context, teamName string) (domain.Team, []domain.User, error) {
 	team := domain.Team{Name: teamName}
 
-	selectUsersQuery := `SELECT user_id, username, team_name, is_active
+	selectUsersQuery := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
 FROM users
 WHERE team_name = $1;`
 	userRows, err := r.db.QueryContext(ctx, selectUsersQuery, teamName)
@@ -44,19 +47,10 @@ WHERE team_name = $1;`
 
 	var users []domain.User
 	for userRows.Next() {
-		var user_id, username, team_name string
-		var is_active bool
-		err = userRows.Scan(&user_id, &username, &team_name, &is_active)
+		user, err := scanUser(userRows)
 		if err != nil {
 			return domain.Team{}, nil, err
 		}
-
-		user := domain.User{
-			ID:       user_id,
-			Username: username,
-			TeamName: team_name,
-			IsActive: is_active,
-		}
 		users = append(users, user)
 	}
 	if err := userRows.Err(); err != nil {
@@ -66,10 +60,14 @@ WHERE team_name = $1;`
 	return team, users, nil
 }
 
+// CreateTeamWithMembers creates the team and upserts its members. resolve
+// is called with the requested users that already exist, locked for the
+// rest of the transaction, and returns the members to actually write.
 func (r *PostgresTeamRepository) CreateTeamWithMembers(
 	ctx context.Context,
 	teamName string,
 	members []service.TeamMemberInput,
+	resolve func(ctx context.Context, existing []domain.User) ([]service.TeamMemberInput, error),
 ) (domain.Team, []domain.User, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -83,13 +81,48 @@ func (r *PostgresTeamRepository) CreateTeamWithMembers(
 		return domain.Team{}, nil, err
 	}
 
-	insertUsersQuery := `INSERT INTO users (user_id, username, team_name, is_active) 
-VALUES ($1, $2, $3, $4)
+	requestedIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		requestedIDs = append(requestedIDs, member.UserID)
+	}
+	lockUsersQuery := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
+FROM users
+WHERE user_id = ANY($1)
+ORDER BY user_id
+FOR UPDATE;`
+	existingRows, err := tx.QueryContext(ctx, lockUsersQuery, pq.Array(requestedIDs))
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	defer existingRows.Close()
+
+	previousTeams := make(map[string]string)
+	var existing []domain.User
+	for existingRows.Next() {
+		user, err := scanUser(existingRows)
+		if err != nil {
+			return domain.Team{}, nil, err
+		}
+		existing = append(existing, user)
+		previousTeams[user.ID] = user.TeamName
+	}
+	if err := existingRows.Err(); err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	members, err = resolve(ctx, existing)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	insertUsersQuery := `INSERT INTO users (user_id, username, team_name, is_active, tags)
+VALUES ($1, $2, $3, $4, COALESCE($5::text[], '{}'))
 ON CONFLICT (user_id)
 DO UPDATE SET
 username = EXCLUDED.username,
     team_name = EXCLUDED.team_name,
-    is_active = EXCLUDED.is_active;`
+    is_active = EXCLUDED.is_active,
+    tags = CASE WHEN $5::text[] IS NULL THEN users.tags ELSE EXCLUDED.tags END;`
 
 	for _, member := range members {
 		_, err = tx.ExecContext(
@@ -99,17 +132,35 @@ username = EXCLUDED.username,
 			member.Username,
 			teamName,
 			member.IsActive,
+			pq.Array(member.Tags),
 		)
 		if err != nil {
 			return domain.Team{}, nil, err
 		}
 	}
 
+	var joinedIDs, movedIDs []string
+	for _, member := range members {
+		if previousTeams[member.UserID] != "" {
+			movedIDs = append(movedIDs, member.UserID)
+			continue
+		}
+		joinedIDs = append(joinedIDs, member.UserID)
+	}
+	err = recordMembership(ctx, tx, joinedIDs, teamName, domain.MembershipTeamCreated)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	err = recordMembership(ctx, tx, movedIDs, teamName, domain.MembershipMoved)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
 	team := domain.Team{
 		Name: teamName,
 	}
 
-	selectUsersQuery := `SELECT user_id, username, team_name, is_active
+	selectUsersQuery := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
 FROM users
 WHERE team_name = $1;`
 	rows, err := tx.QueryContext(ctx, selectUsersQuery, teamName)
@@ -120,28 +171,475 @@ WHERE team_name = $1;`
 
 	var users []domain.User
 	for rows.Next() {
-		var user_id, username, team_name string
-		var is_active bool
-		err = rows.Scan(&user_id, &username, &team_name, &is_active)
+		user, err := scanUser(rows)
 		if err != nil {
 			return domain.Team{}, nil, err
 		}
-
-		user := domain.User{
-			ID:       user_id,
-			Username: username,
-			TeamName: team_name,
-			IsActive: is_active,
-		}
 		users = append(users, user)
 	}
 	if err := rows.Err(); err != nil {
 		return domain.Team{}, nil, err
 	}
 
+	created := service.TeamCreatedData{
+		TeamName: teamName,
+		Members:  make([]service.TeamMemberData, 0, len(users)),
+	}
+	for _, u := range users {
+		created.Members = append(created.Members, service.TeamMemberData{
+			UserID:   u.ID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+	err = insertEvent(ctx, tx, domain.EventTeamCreated, created)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	var moves []domain.Event
+	for _, u := range users {
+		if !slices.Contains(movedIDs, u.ID) {
+			continue
+		}
+		e, err := service.NewEvent(ctx, domain.EventUserTeamChanged, service.UserTeamChangedData{
+			UserID:       u.ID,
+			Username:     u.Username,
+			FromTeamName: previousTeams[u.ID],
+			ToTeamName:   teamName,
+		})
+		if err != nil {
+			return domain.Team{}, nil, err
+		}
+		moves = append(moves, e)
+	}
+	err = insertEvents(ctx, tx, moves)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditTeamCreate, service.AuditEntityTeam,
+		service.AuditChange{EntityID: teamName, After: service.TeamSnapshot{Team: team, Members: users}})
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	previous := make(map[string]domain.User, len(existing))
+	for _, u := range existing {
+		previous[u.ID] = u
+	}
+	changes := make([]service.AuditChange, 0, len(users))
+	for _, u := range users {
+		var before any
+		if prev, ok := previous[u.ID]; ok {
+			before = prev
+		}
+		changes = append(changes, service.AuditChange{EntityID: u.ID, Before: before, After: u})
+	}
+	err = insertAudit(ctx, tx, service.AuditUserUpsert, service.AuditEntityUser, changes...)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return domain.Team{}, nil, err
 	}
 
 	return team, users, nil
 }
+
+// AddTeamMember adds a new user to the team or attaches an existing user
+// who belongs to no team. It returns sql.ErrNoRows when the user is
+// already in a team, this one included.
+func (r *PostgresTeamRepository) AddTeamMember(
+	ctx context.Context,
+	teamName string,
+	member service.TeamMemberInput,
+) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback()
+
+	var before any
+	prev, err := lockUser(ctx, tx, member.UserID)
+	if err == nil {
+		before = prev
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, err
+	}
+
+	insertUserQuery := `INSERT INTO users (user_id, username, team_name, is_active, tags)
+VALUES ($1, $2, $3, $4, COALESCE($5::text[], '{}'))
+ON CONFLICT (user_id)
+DO UPDATE SET
+    username = EXCLUDED.username,
+    team_name = EXCLUDED.team_name,
+    is_active = EXCLUDED.is_active,
+    tags = CASE WHEN $5::text[] IS NULL THEN users.tags ELSE EXCLUDED.tags END
+WHERE users.team_name IS NULL
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	row := tx.QueryRowContext(
+		ctx,
+		insertUserQuery,
+		member.UserID,
+		member.Username,
+		teamName,
+		member.IsActive,
+		pq.Array(member.Tags),
+	)
+	user, err := scanUser(row)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = recordMembership(ctx, tx, []string{user.ID}, teamName, domain.MembershipAdded)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertEvent(ctx, tx, domain.EventTeamMemberAdded, teamMembershipData(user))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditTeamMemberAdd, service.AuditEntityUser,
+		service.AuditChange{EntityID: user.ID, Before: before, After: user})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// RenameTeamMember changes the username of a member of the team.
+// It returns sql.ErrNoRows when the user is not in the team.
+func (r *PostgresTeamRepository) RenameTeamMember(
+	ctx context.Context,
+	teamName, userID, username string,
+) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockUser(ctx, tx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	renameQuery := `UPDATE users
+SET username = $3
+WHERE user_id = $2
+  AND team_name = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	row := tx.QueryRowContext(ctx, renameQuery, teamName, userID, username)
+	user, err := scanUser(row)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertEvent(ctx, tx, domain.EventTeamMemberUpdated, teamMembershipData(user))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditTeamMemberUpdate, service.AuditEntityUser,
+		service.AuditChange{EntityID: user.ID, Before: before, After: user})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// lockTeam locks the team row for the rest of tx, serializing changes of
+// the team's configuration. It returns sql.ErrNoRows when the team does
+// not exist.
+func lockTeam(ctx context.Context, tx *sql.Tx, teamName string) error {
+	query := `SELECT team_name FROM teams WHERE team_name = $1 FOR UPDATE;`
+	return tx.QueryRowContext(ctx, query, teamName).Scan(&teamName)
+}
+
+// recordMembership ends the current membership of the users and starts one
+// in teamName, skipping users who are already in it. An empty teamName only
+// ends the current membership.
+func recordMembership(ctx context.Context, tx *sql.Tx, userIDs []string, teamName, reason string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	closeQuery := `UPDATE team_memberships
+SET left_at = NOW()
+WHERE user_id = ANY($1)
+  AND left_at IS NULL
+  AND team_name <> $2;`
+	_, err := tx.ExecContext(ctx, closeQuery, pq.Array(userIDs), teamName)
+	if err != nil {
+		return err
+	}
+	if teamName == "" {
+		return nil
+	}
+
+	openQuery := `INSERT INTO team_memberships (user_id, team_name, reason, actor)
+SELECT u.user_id, $2, $3, NULLIF($4, '')
+FROM unnest($1::text[]) AS u(user_id)
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM team_memberships m
+    WHERE m.user_id = u.user_id
+      AND m.left_at IS NULL
+);`
+	_, err = tx.ExecContext(ctx, openQuery, pq.Array(userIDs), teamName, reason, service.ActorFrom(ctx))
+	return err
+}
+
+func teamMembershipData(u domain.User) service.TeamMembershipData {
+	return service.TeamMembershipData{
+		TeamName: u.TeamName,
+		Member: service.TeamMemberData{
+			UserID:   u.ID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		},
+	}
+}
+
+func (r *PostgresTeamRepository) GetSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	return r.getSettings(ctx, r.db, teamName)
+}
+
+func (r *PostgresTeamRepository) getSettings(ctx context.Context, q queryer, teamName string) (domain.TeamSettings, error) {
+	query := `SELECT team_name, min_reviewers, max_reviewers, max_open_reviews, capacity_policy,
+       approval_policy, required_approvals, review_sla_minutes, sla_action
+FROM team_settings
+WHERE team_name = $1;`
+	row := q.QueryRowContext(ctx, query, teamName)
+
+	s, err := scanTeamSettings(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		s = domain.DefaultTeamSettings(teamName)
+	} else if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	s.FallbackTeams, err = r.getFallbackTeams(ctx, q, teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return s, nil
+}
+
+// UpsertSettings locks the team, passes its current settings to apply and
+// stores what apply returns. It returns sql.ErrNoRows when the team does
+// not exist.
+func (r *PostgresTeamRepository) UpsertSettings(
+	ctx context.Context,
+	teamName string,
+	apply func(ctx context.Context, current domain.TeamSettings) (domain.TeamSettings, error),
+) (domain.TeamSettings, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTeam(ctx, tx, teamName); err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	current, err := r.getSettings(ctx, tx, teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	settings, err := apply(ctx, current)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	upsertSettingsQuery := `INSERT INTO team_settings
+    (team_name, min_reviewers, max_reviewers, max_open_reviews, capacity_policy, approval_policy, required_approvals,
+     review_sla_minutes, sla_action)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (team_name)
+DO UPDATE SET
+    min_reviewers = EXCLUDED.min_reviewers,
+    max_reviewers = EXCLUDED.max_reviewers,
+    max_open_reviews = EXCLUDED.max_open_reviews,
+    capacity_policy = EXCLUDED.capacity_policy,
+    approval_policy = EXCLUDED.approval_policy,
+    required_approvals = EXCLUDED.required_approvals,
+    review_sla_minutes = EXCLUDED.review_sla_minutes,
+    sla_action = EXCLUDED.sla_action
+RETURNING team_name, min_reviewers, max_reviewers, max_open_reviews, capacity_policy,
+          approval_policy, required_approvals, review_sla_minutes, sla_action;`
+	row := tx.QueryRowContext(
+		ctx,
+		upsertSettingsQuery,
+		settings.TeamName,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.MaxOpenReviews,
+		settings.CapacityPolicy,
+		settings.ApprovalPolicy,
+		settings.RequiredApprovals,
+		settings.ReviewSLAMinutes,
+		settings.SLAAction,
+	)
+
+	s, err := scanTeamSettings(row)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	deleteFallbacksQuery := `DELETE FROM team_fallbacks WHERE team_name = $1;`
+	_, err = tx.ExecContext(ctx, deleteFallbacksQuery, settings.TeamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	insertFallbackQuery := `INSERT INTO team_fallbacks (team_name, fallback_team_name, priority)
+VALUES ($1, $2, $3);`
+	for i, fallback := range settings.FallbackTeams {
+		_, err = tx.ExecContext(ctx, insertFallbackQuery, settings.TeamName, fallback, i)
+		if err != nil {
+			return domain.TeamSettings{}, err
+		}
+	}
+
+	s.FallbackTeams, err = r.getFallbackTeams(ctx, tx, settings.TeamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditTeamSettings, service.AuditEntityTeam,
+		service.AuditChange{EntityID: teamName, Before: current, After: s})
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return s, nil
+}
+
+func (r *PostgresTeamRepository) getFallbackTeams(ctx context.Context, q queryer, teamName string) ([]string, error) {
+	query := `SELECT fallback_team_name
+FROM team_fallbacks
+WHERE team_name = $1
+ORDER BY priority;`
+	rows, err := q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []string
+	for rows.Next() {
+		var team string
+		err = rows.Scan(&team)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (r *PostgresTeamRepository) GetOwnershipRules(ctx context.Context, teamName string) ([]domain.OwnershipRule, error) {
+	return getOwnershipRules(ctx, r.db, teamName)
+}
+
+func getOwnershipRules(ctx context.Context, q queryer, teamName string) ([]domain.OwnershipRule, error) {
+	query := `SELECT pattern, owners
+FROM ownership_rules
+WHERE team_name = $1
+ORDER BY position;`
+	rows, err := q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.OwnershipRule
+	for rows.Next() {
+		var rule domain.OwnershipRule
+		err = rows.Scan(&rule.Pattern, (*pq.StringArray)(&rule.Owners))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ReplaceOwnershipRules replaces the team's rules. It returns
+// sql.ErrNoRows when the team does not exist.
+func (r *PostgresTeamRepository) ReplaceOwnershipRules(
+	ctx context.Context,
+	teamName string,
+	rules []domain.OwnershipRule,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTeam(ctx, tx, teamName); err != nil {
+		return err
+	}
+
+	before, err := getOwnershipRules(ctx, tx, teamName)
+	if err != nil {
+		return err
+	}
+
+	deleteRulesQuery := `DELETE FROM ownership_rules WHERE team_name = $1;`
+	_, err = tx.ExecContext(ctx, deleteRulesQuery, teamName)
+	if err != nil {
+		return err
+	}
+
+	insertRuleQuery := `INSERT INTO ownership_rules (team_name, position, pattern, owners)
+VALUES ($1, $2, $3, $4);`
+	for i, rule := range rules {
+		_, err = tx.ExecContext(ctx, insertRuleQuery, teamName, i, rule.Pattern, pq.Array(rule.Owners))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = insertAudit(ctx, tx, service.AuditTeamCodeowners, service.AuditEntityTeam,
+		service.AuditChange{EntityID: teamName, Before: before, After: rules})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,9 +2,16 @@ package repository
 
 import (
 	"PR_project/internal/domain"
+	"PR_project/internal/service"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type PostgresUserRepository struct {
@@ -30,28 +37,103 @@ func (r *PostgresUserRepository) UserExists(ctx context.Context, userID string)
 }
 
 func (r *PostgresUserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockUser(ctx, tx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
 	updateUsersQuery := `UPDATE users
 SET is_active = $2
 WHERE user_id = $1
-RETURNING user_id, username, team_name, is_active;`
-	row := r.db.QueryRowContext(ctx, updateUsersQuery, userID, isActive)
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	row := tx.QueryRowContext(ctx, updateUsersQuery, userID, isActive)
+
+	u, err := scanUser(row)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if before.IsActive != u.IsActive {
+		err = insertEvent(ctx, tx, domain.EventUserActivationChanged, service.UserActivationData{
+			UserID:   u.ID,
+			TeamName: u.TeamName,
+			IsActive: u.IsActive,
+		})
+		if err != nil {
+			return domain.User{}, err
+		}
+	}
 
-	var u domain.User
-	err := row.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	err = insertAudit(ctx, tx, service.AuditUserSetActive, service.AuditEntityUser,
+		service.AuditChange{EntityID: u.ID, Before: before, After: u})
 	if err != nil {
 		return domain.User{}, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+
 	return u, nil
 }
 
-func (r *PostgresUserRepository) GetReviews(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+// lockUser locks the user row for the rest of tx and returns the user as
+// it is before the change. It returns sql.ErrNoRows when the user does
+// not exist.
+func lockUser(ctx context.Context, tx *sql.Tx, userID string) (domain.User, error) {
+	query := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
+FROM users
+WHERE user_id = $1
+FOR UPDATE;`
+	return scanUser(tx.QueryRowContext(ctx, query, userID))
+}
+
+// updateUser applies a single-row update returning the user and audits it
+// with the user's previous state, all in one transaction.
+func (r *PostgresUserRepository) updateUser(ctx context.Context, action, query, userID string, args ...any) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockUser(ctx, tx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	u, err := scanUser(tx.QueryRowContext(ctx, query, append([]any{userID}, args...)...))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertAudit(ctx, tx, action, service.AuditEntityUser,
+		service.AuditChange{EntityID: u.ID, Before: before, After: u})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+
+	return u, nil
+}
+
+func (r *PostgresUserRepository) GetReviews(ctx context.Context, userID string, includeClosed bool) ([]domain.PullRequest, error) {
 	selectPrsQuery := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
 FROM pull_requests pr
 JOIN pr_reviewers rev
     ON rev.pull_request_id = pr.pull_request_id
-WHERE rev.user_id = $1;`
-	rows, err := r.db.QueryContext(ctx, selectPrsQuery, userID)
+WHERE rev.user_id = $1
+  AND ($2 OR pr.status <> 'CLOSED');`
+	rows, err := r.db.QueryContext(ctx, selectPrsQuery, userID, includeClosed)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +141,8 @@ WHERE rev.user_id = $1;`
 
 	var pullRequests []domain.PullRequest
 	for rows.Next() {
-		var prID, prName, authorID, status string
+		var prID, prName, authorID string
+		var status domain.PrStatus
 		err = rows.Scan(&prID, &prName, &authorID, &status)
 		if err != nil {
 			return nil, err
@@ -81,13 +164,12 @@ WHERE rev.user_id = $1;`
 }
 
 func (r *PostgresUserRepository) GetUserByID(ctx context.Context, userID string) (domain.User, error) {
-	query := `SELECT user_id, username, team_name, is_active
+	query := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
 FROM users
 WHERE user_id = $1;
 `
 	row := r.db.QueryRowContext(ctx, query, userID)
-	var u domain.User
-	err := row.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	u, err := scanUser(row)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -99,11 +181,20 @@ func (r *PostgresUserRepository) GetActiveTeamMembersExcept(
 	teamName string,
 	excludeID string,
 ) ([]domain.User, error) {
-	query := `SELECT user_id, username, team_name, is_active
+	query := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
 FROM users
 WHERE team_name = $1
   AND is_active = TRUE
-  AND user_id <> $2`
+  AND user_id <> $2
+  AND NOT EXISTS (
+      SELECT 1
+      FROM user_absences a
+      WHERE a.user_id = users.user_id
+        AND a.cancelled_at IS NULL
+        AND a.starts_at <= NOW()
+        AND a.ends_at > NOW()
+  )
+ORDER BY user_id`
 
 	rows, err := r.db.QueryContext(ctx, query, teamName, excludeID)
 	if err != nil {
@@ -113,24 +204,787 @@ WHERE team_name = $1
 
 	var users []domain.User
 	for rows.Next() {
-		var userID, username, teamname string
-		var isActive bool
-		err = rows.Scan(&userID, &username, &teamname, &isActive)
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// getUsersByIDs returns the users with the given ids, keyed by id. Unknown
+// ids are left out.
+func getUsersByIDs(ctx context.Context, q queryer, userIDs []string) (map[string]domain.User, error) {
+	query := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
+FROM users
+WHERE user_id = ANY($1);`
+	rows, err := q.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make(map[string]domain.User, len(userIDs))
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users[u.ID] = u
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *PostgresUserRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	query := `SELECT rev.user_id, COUNT(*)
+FROM pr_reviewers rev
+JOIN pull_requests pr
+    ON pr.pull_request_id = rev.pull_request_id
+WHERE rev.user_id = ANY($1)
+  AND pr.status = 'OPEN'
+GROUP BY rev.user_id;`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loads := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int
+		err = rows.Scan(&userID, &count)
+		if err != nil {
+			return nil, err
+		}
+		loads[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return loads, nil
+}
+
+func (r *PostgresUserRepository) SetTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	query := `UPDATE users
+SET tags = $2
+WHERE user_id = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	return r.updateTags(ctx, query, userID, tags)
+}
+
+func (r *PostgresUserRepository) AddTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	query := `UPDATE users
+SET tags = ARRAY(SELECT DISTINCT t FROM unnest(tags || $2::text[]) AS t ORDER BY t)
+WHERE user_id = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	return r.updateTags(ctx, query, userID, tags)
+}
+
+func (r *PostgresUserRepository) RemoveTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	query := `UPDATE users
+SET tags = ARRAY(SELECT t FROM unnest(tags) AS t WHERE t <> ALL($2::text[]) ORDER BY t)
+WHERE user_id = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	return r.updateTags(ctx, query, userID, tags)
+}
+
+func (r *PostgresUserRepository) updateTags(ctx context.Context, query, userID string, tags []string) (domain.User, error) {
+	return r.updateUser(ctx, service.AuditUserTags, query, userID, pq.Array(tags))
+}
+
+func (r *PostgresUserRepository) CreateAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error) {
+	query := `INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING absence_id, user_id, starts_at, ends_at, reason, created_at, cancelled_at;`
+	return r.writeAbsence(ctx, service.AuditUserAbsenceCreate, query,
+		absence.UserID, absence.StartsAt, absence.EndsAt, absence.Reason)
+}
+
+func (r *PostgresUserRepository) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	query := `SELECT absence_id, user_id, starts_at, ends_at, reason, created_at, cancelled_at
+FROM user_absences
+WHERE user_id = $1
+ORDER BY starts_at;`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var absences []domain.Absence
+	for rows.Next() {
+		var a domain.Absence
+		err = rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.CancelledAt)
 		if err != nil {
 			return nil, err
 		}
+		absences = append(absences, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		u := domain.User{
-			ID:       userID,
-			Username: username,
-			TeamName: teamname,
-			IsActive: isActive,
+	return absences, nil
+}
+
+func (r *PostgresUserRepository) CancelAbsence(ctx context.Context, absenceID int64, cancelledAt time.Time) (domain.Absence, error) {
+	query := `UPDATE user_absences
+SET cancelled_at = COALESCE(cancelled_at, $2)
+WHERE absence_id = $1
+RETURNING absence_id, user_id, starts_at, ends_at, reason, created_at, cancelled_at;`
+	return r.writeAbsence(ctx, service.AuditUserAbsenceCancel, query, absenceID, cancelledAt)
+}
+
+// writeAbsence runs a statement returning one absence and audits the
+// result under the absent user, in one transaction.
+func (r *PostgresUserRepository) writeAbsence(ctx context.Context, action, query string, args ...any) (domain.Absence, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Absence{}, err
+	}
+	defer tx.Rollback()
+
+	var a domain.Absence
+	err = tx.QueryRowContext(ctx, query, args...).
+		Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.CancelledAt)
+	if err != nil {
+		return domain.Absence{}, err
+	}
+
+	err = insertAudit(ctx, tx, action, service.AuditEntityUser, service.AuditChange{EntityID: a.UserID, After: a})
+	if err != nil {
+		return domain.Absence{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Absence{}, err
+	}
+
+	return a, nil
+}
+
+func (r *PostgresUserRepository) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (domain.User, error) {
+	query := `UPDATE users
+SET max_open_reviews = $2
+WHERE user_id = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	return r.updateUser(ctx, service.AuditUserCapacity, query, userID, maxOpenReviews)
+}
+
+// DeactivateUsers deactivates the target users and hands their OPEN reviews
+// over in one transaction. plan is called with a snapshot read under row
+// locks and returns one handover per slot. Every step is a single statement,
+// so the cost does not grow in round trips with the number of users or
+// reviews. It returns sql.ErrNoRows when one of the listed users does not
+// exist or is not in the target team.
+func (r *PostgresUserRepository) DeactivateUsers(
+	ctx context.Context,
+	target domain.DeactivationTarget,
+	plan func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error),
+) ([]domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	snapshot, err := r.deactivationSnapshot(ctx, tx, target)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	handovers, err := plan(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivateQuery := `UPDATE users
+SET is_active = FALSE,
+    team_name = CASE WHEN $2 THEN NULL ELSE team_name END
+WHERE user_id = ANY($1)
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	rows, err := tx.QueryContext(ctx, deactivateQuery, pq.Array(userIDs), target.LeaveTeam)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
 		}
 		users = append(users, u)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	slices.SortFunc(users, func(a, b domain.User) int { return strings.Compare(a.ID, b.ID) })
+
+	if target.LeaveTeam {
+		err = recordMembership(ctx, tx, userIDs, "", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = applyHandovers(ctx, tx, handovers)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := deactivationEvents(ctx, snapshot, handovers, target.LeaveTeam)
+	if err != nil {
+		return nil, err
+	}
+	err = insertEvents(ctx, tx, events)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertDeactivationAudit(ctx, tx, snapshot.Users, users, target.LeaveTeam)
+	if err != nil {
+		return nil, err
+	}
+	err = insertHandoverAudit(ctx, tx, handovers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return users, nil
 }
+
+// MoveUserToTeam moves the user into teamName in one transaction, ending
+// their current membership. plan is called with a snapshot of the user's
+// open reviews and their old teammates and returns the reviews to hand
+// over, if any. It returns sql.ErrNoRows when the user does not exist.
+func (r *PostgresUserRepository) MoveUserToTeam(
+	ctx context.Context,
+	userID, teamName string,
+	plan func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error),
+) (domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback()
+
+	snapshot, err := r.deactivationSnapshot(ctx, tx, domain.DeactivationTarget{UserIDs: []string{userID}})
+	if err != nil {
+		return domain.User{}, err
+	}
+	before := snapshot.Users[0]
+
+	handovers, err := plan(ctx, snapshot)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	moveQuery := `UPDATE users
+SET team_name = $2
+WHERE user_id = $1
+RETURNING user_id, username, team_name, is_active, tags, max_open_reviews;`
+	row := tx.QueryRowContext(ctx, moveQuery, userID, teamName)
+	user, err := scanUser(row)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = recordMembership(ctx, tx, []string{userID}, teamName, domain.MembershipMoved)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = applyHandovers(ctx, tx, handovers)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	moved, err := service.NewEvent(ctx, domain.EventUserTeamChanged, service.UserTeamChangedData{
+		UserID:       user.ID,
+		Username:     user.Username,
+		FromTeamName: before.TeamName,
+		ToTeamName:   user.TeamName,
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	events, err := handoverEvents(ctx, snapshot, handovers)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = insertEvents(ctx, tx, append([]domain.Event{moved}, events...))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	err = insertAudit(ctx, tx, service.AuditUserTeamMove, service.AuditEntityUser,
+		service.AuditChange{EntityID: user.ID, Before: before, After: user})
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = insertHandoverAudit(ctx, tx, handovers)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *PostgresUserRepository) ListMemberships(ctx context.Context, userID string) ([]domain.TeamMembership, error) {
+	query := `SELECT user_id, team_name, joined_at, left_at, reason, COALESCE(actor, '')
+FROM team_memberships
+WHERE user_id = $1
+ORDER BY joined_at, membership_id;`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []domain.TeamMembership
+	for rows.Next() {
+		var m domain.TeamMembership
+		var leftAt sql.NullTime
+		err = rows.Scan(&m.UserID, &m.TeamName, &m.JoinedAt, &leftAt, &m.Reason, &m.Actor)
+		if err != nil {
+			return nil, err
+		}
+		if leftAt.Valid {
+			m.LeftAt = &leftAt.Time
+		}
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+func (r *PostgresUserRepository) deactivationSnapshot(
+	ctx context.Context,
+	tx *sql.Tx,
+	target domain.DeactivationTarget,
+) (domain.DeactivationSnapshot, error) {
+	var snapshot domain.DeactivationSnapshot
+
+	lockUsersQuery := `SELECT user_id, username, team_name, is_active, tags, max_open_reviews
+FROM users
+WHERE ($1 = '' OR team_name = $1)
+  AND (cardinality($2::text[]) = 0 OR user_id = ANY($2))
+ORDER BY user_id
+FOR UPDATE;`
+	rows, err := tx.QueryContext(ctx, lockUsersQuery, target.TeamName, pq.Array(target.UserIDs))
+	if err != nil {
+		return snapshot, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.Users = append(snapshot.Users, u)
+		userIDs = append(userIDs, u.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return snapshot, err
+	}
+	if len(target.UserIDs) > 0 && len(snapshot.Users) != len(target.UserIDs) {
+		return snapshot, sql.ErrNoRows
+	}
+
+	// Locking the pull requests keeps merges and closes from racing the handover.
+	slotsQuery := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, COALESCE(author.team_name, ''),
+       rev.user_id
+FROM pr_reviewers rev
+JOIN pull_requests pr
+    ON pr.pull_request_id = rev.pull_request_id
+JOIN users author
+    ON author.user_id = pr.author_id
+WHERE rev.user_id = ANY($1)
+  AND pr.status = 'OPEN'
+ORDER BY pr.created_at, pr.pull_request_id, rev.user_id
+FOR UPDATE OF pr;`
+	slotRows, err := tx.QueryContext(ctx, slotsQuery, pq.Array(userIDs))
+	if err != nil {
+		return snapshot, err
+	}
+	defer slotRows.Close()
+
+	// Reviews are handed over within the author's team, so candidates come
+	// from the authors' teams.
+	var prIDs, teams []string
+	for slotRows.Next() {
+		var slot domain.OpenReviewSlot
+		err = slotRows.Scan(
+			&slot.PullRequestID,
+			&slot.Name,
+			&slot.AuthorID,
+			&slot.AuthorTeam,
+			&slot.ReviewerID,
+		)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.Slots = append(snapshot.Slots, slot)
+		prIDs = append(prIDs, slot.PullRequestID)
+		if slot.AuthorTeam != "" && !slices.Contains(teams, slot.AuthorTeam) {
+			teams = append(teams, slot.AuthorTeam)
+		}
+	}
+	if err := slotRows.Err(); err != nil {
+		return snapshot, err
+	}
+
+	reviewersQuery := `SELECT pull_request_id, user_id
+FROM pr_reviewers
+WHERE pull_request_id = ANY($1)
+ORDER BY pull_request_id, user_id;`
+	revRows, err := tx.QueryContext(ctx, reviewersQuery, pq.Array(prIDs))
+	if err != nil {
+		return snapshot, err
+	}
+	defer revRows.Close()
+
+	reviewers := make(map[string][]string, len(prIDs))
+	for revRows.Next() {
+		var prID, userID string
+		err = revRows.Scan(&prID, &userID)
+		if err != nil {
+			return snapshot, err
+		}
+		reviewers[prID] = append(reviewers[prID], userID)
+	}
+	if err := revRows.Err(); err != nil {
+		return snapshot, err
+	}
+	for i := range snapshot.Slots {
+		snapshot.Slots[i].ReviewerIDs = reviewers[snapshot.Slots[i].PullRequestID]
+	}
+
+	candidatesQuery := `SELECT u.user_id, u.username, u.team_name, u.is_active, u.tags, u.max_open_reviews,
+       COALESCE(u.max_open_reviews, ts.max_open_reviews),
+       load.open_reviews
+FROM users u
+LEFT JOIN team_settings ts
+    ON ts.team_name = u.team_name
+CROSS JOIN LATERAL (
+    SELECT COUNT(*) AS open_reviews
+    FROM pr_reviewers rev
+    JOIN pull_requests pr
+        ON pr.pull_request_id = rev.pull_request_id
+    WHERE rev.user_id = u.user_id
+      AND pr.status = 'OPEN'
+) load
+WHERE u.team_name IS NOT NULL
+  AND ($3 OR u.team_name = ANY($2))
+  AND u.is_active = TRUE
+  AND u.user_id <> ALL($1)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM user_absences a
+      WHERE a.user_id = u.user_id
+        AND a.cancelled_at IS NULL
+        AND a.starts_at <= NOW()
+        AND a.ends_at > NOW()
+  )
+ORDER BY u.user_id;`
+	candRows, err := tx.QueryContext(
+		ctx,
+		candidatesQuery,
+		pq.Array(userIDs),
+		pq.Array(teams),
+		target.AnyTeam,
+	)
+	if err != nil {
+		return snapshot, err
+	}
+	defer candRows.Close()
+
+	for candRows.Next() {
+		var c domain.ReviewerCandidate
+		var capacity sql.NullInt64
+		c.User, err = scanUser(withExtra(candRows, &capacity, &c.OpenReviews))
+		if err != nil {
+			return snapshot, err
+		}
+		if capacity.Valid {
+			v := int(capacity.Int64)
+			c.Capacity = &v
+		}
+		snapshot.Candidates = append(snapshot.Candidates, c)
+	}
+	if err := candRows.Err(); err != nil {
+		return snapshot, err
+	}
+
+	authoredQuery := `SELECT pull_request_id
+FROM pull_requests
+WHERE author_id = ANY($1)
+  AND status IN ('OPEN', 'DRAFT')
+ORDER BY created_at, pull_request_id;`
+	authoredRows, err := tx.QueryContext(ctx, authoredQuery, pq.Array(userIDs))
+	if err != nil {
+		return snapshot, err
+	}
+	defer authoredRows.Close()
+
+	for authoredRows.Next() {
+		var prID string
+		err = authoredRows.Scan(&prID)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.AuthoredPullRequestIDs = append(snapshot.AuthoredPullRequestIDs, prID)
+	}
+	if err := authoredRows.Err(); err != nil {
+		return snapshot, err
+	}
+
+	return snapshot, nil
+}
+
+// applyHandovers removes the previous reviewers and inserts their
+// replacements with rationale, each as a single statement.
+func applyHandovers(ctx context.Context, tx *sql.Tx, handovers []domain.ReviewHandover) error {
+	if len(handovers) == 0 {
+		return nil
+	}
+
+	type reviewerRow struct {
+		PullRequestID  string    `json:"pull_request_id"`
+		UserID         string    `json:"user_id"`
+		Strategy       string    `json:"strategy"`
+		Reason         string    `json:"reason"`
+		FallbackTeam   string    `json:"fallback_team"`
+		CandidatePool  []string  `json:"candidate_pool"`
+		ReplacedUserID string    `json:"replaced_user_id"`
+		AssignedAt     time.Time `json:"assigned_at"`
+	}
+
+	prIDs := make([]string, 0, len(handovers))
+	fromIDs := make([]string, 0, len(handovers))
+	var assigned []reviewerRow
+	for _, h := range handovers {
+		prIDs = append(prIDs, h.PullRequestID)
+		fromIDs = append(fromIDs, h.FromUserID)
+		if h.Unassigned() {
+			continue
+		}
+		pool := h.To.CandidatePool
+		if pool == nil {
+			pool = []string{}
+		}
+		assigned = append(assigned, reviewerRow{
+			PullRequestID:  h.PullRequestID,
+			UserID:         h.To.UserID,
+			Strategy:       h.To.Strategy,
+			Reason:         h.To.Reason,
+			FallbackTeam:   h.To.FallbackTeam,
+			CandidatePool:  pool,
+			ReplacedUserID: h.FromUserID,
+			AssignedAt:     h.To.AssignedAt,
+		})
+	}
+
+	deleteQuery := `DELETE FROM pr_reviewers rev
+USING unnest($1::text[], $2::text[]) AS h(pull_request_id, user_id)
+WHERE rev.pull_request_id = h.pull_request_id
+  AND rev.user_id = h.user_id;`
+	_, err := tx.ExecContext(ctx, deleteQuery, pq.Array(prIDs), pq.Array(fromIDs))
+	if err != nil {
+		return err
+	}
+
+	err = retireRationale(ctx, tx, prIDs, fromIDs)
+	if err != nil {
+		return err
+	}
+
+	if len(assigned) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(assigned)
+	if err != nil {
+		return err
+	}
+
+	insertReviewersQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id)
+SELECT x.pull_request_id, x.user_id
+FROM jsonb_to_recordset($1::jsonb) AS x(pull_request_id text, user_id text);`
+	_, err = tx.ExecContext(ctx, insertReviewersQuery, string(payload))
+	if err != nil {
+		return err
+	}
+
+	insertRationaleQuery := `INSERT INTO pr_reviewer_rationale
+    (pull_request_id, user_id, strategy, reason, fallback_team, candidate_pool, replaced_user_id, assigned_at)
+SELECT x.pull_request_id, x.user_id, x.strategy, x.reason, NULLIF(x.fallback_team, ''), x.candidate_pool,
+       NULLIF(x.replaced_user_id, ''), x.assigned_at
+FROM jsonb_to_recordset($1::jsonb)
+    AS x(pull_request_id text, user_id text, strategy text, reason text, fallback_team text,
+         candidate_pool text[], replaced_user_id text, assigned_at timestamptz);`
+	_, err = tx.ExecContext(ctx, insertRationaleQuery, string(payload))
+	return err
+}
+
+// insertDeactivationAudit records every user whose status or team changed.
+// Removals from the team are recorded as such, other deactivations as a
+// status change.
+func insertDeactivationAudit(ctx context.Context, tx *sql.Tx, before, after []domain.User, leaveTeam bool) error {
+	changed := make(map[string]domain.User, len(before))
+	for _, u := range before {
+		if u.IsActive || leaveTeam {
+			changed[u.ID] = u
+		}
+	}
+	changes := make([]service.AuditChange, 0, len(changed))
+	for _, u := range after {
+		if prev, ok := changed[u.ID]; ok {
+			changes = append(changes, service.AuditChange{EntityID: u.ID, Before: prev, After: u})
+		}
+	}
+
+	action := service.AuditUserSetActive
+	if leaveTeam {
+		action = service.AuditTeamMemberRemove
+	}
+	return insertAudit(ctx, tx, action, service.AuditEntityUser, changes...)
+}
+
+// insertHandoverAudit records every handed over review under its pull
+// request.
+func insertHandoverAudit(ctx context.Context, tx *sql.Tx, handovers []domain.ReviewHandover) error {
+	changes := make([]service.AuditChange, 0, len(handovers))
+	for _, h := range handovers {
+		changes = append(changes, service.AuditChange{EntityID: h.PullRequestID, After: h})
+	}
+	return insertAudit(ctx, tx, service.AuditPrReassign, service.AuditEntityPullRequest, changes...)
+}
+
+// deactivationEvents describes the deactivation for the outbox: one event
+// per user leaving their team, one per user whose status changed and the
+// handover events.
+func deactivationEvents(
+	ctx context.Context,
+	snapshot domain.DeactivationSnapshot,
+	handovers []domain.ReviewHandover,
+	leaveTeam bool,
+) ([]domain.Event, error) {
+	var events []domain.Event
+
+	for _, u := range snapshot.Users {
+		if !leaveTeam || u.TeamName == "" {
+			continue
+		}
+		removed := teamMembershipData(u)
+		removed.Member.IsActive = false
+		e, err := service.NewEvent(ctx, domain.EventTeamMemberRemoved, removed)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	for _, u := range snapshot.Users {
+		if !u.IsActive {
+			continue
+		}
+		e, err := service.NewEvent(ctx, domain.EventUserActivationChanged, service.UserActivationData{
+			UserID:   u.ID,
+			TeamName: u.TeamName,
+			IsActive: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	handed, err := handoverEvents(ctx, snapshot, handovers)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, handed...), nil
+}
+
+// handoverEvents returns one event per handover, carrying the reviewers of
+// the pull request once all its handovers are applied.
+func handoverEvents(
+	ctx context.Context,
+	snapshot domain.DeactivationSnapshot,
+	handovers []domain.ReviewHandover,
+) ([]domain.Event, error) {
+	events := make([]domain.Event, 0, len(handovers))
+
+	slots := make(map[string]domain.OpenReviewSlot, len(snapshot.Slots))
+	reviewers := make(map[string][]string, len(snapshot.Slots))
+	for _, slot := range snapshot.Slots {
+		slots[slot.PullRequestID] = slot
+		reviewers[slot.PullRequestID] = slot.ReviewerIDs
+	}
+	for _, h := range handovers {
+		ids := slices.DeleteFunc(slices.Clone(reviewers[h.PullRequestID]), func(id string) bool {
+			return id == h.FromUserID
+		})
+		if !h.Unassigned() {
+			ids = append(ids, h.To.UserID)
+		}
+		slices.Sort(ids)
+		reviewers[h.PullRequestID] = ids
+	}
+
+	for _, h := range handovers {
+		slot := slots[h.PullRequestID]
+		eventType := domain.EventPrReviewerReassigned
+		if h.Unassigned() {
+			eventType = domain.EventPrReviewerUnassigned
+		}
+		e, err := service.NewEvent(ctx, eventType, service.ReviewerReassignedData{
+			PrEventData: service.PrEventData{
+				PullRequestID: slot.PullRequestID,
+				Name:          slot.Name,
+				AuthorID:      slot.AuthorID,
+				Status:        string(domain.PrStatusOpen),
+				ReviewerIDs:   append([]string{}, reviewers[h.PullRequestID]...),
+			},
+			OldReviewerID: h.FromUserID,
+			NewReviewerID: h.To.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const deliveryColumns = `d.delivery_id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+       d.next_attempt_at, d.last_attempt_at, d.last_status_code, d.last_error, d.delivered_at,
+       d.redelivery_of, d.created_at`
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+func (r *PostgresWebhookRepository) CreateSubscription(
+	ctx context.Context,
+	sub domain.WebhookSubscription,
+) (domain.WebhookSubscription, error) {
+	query := `INSERT INTO webhook_subscriptions (url, secret, event_types)
+VALUES ($1, $2, COALESCE($3::text[], '{}'))
+RETURNING subscription_id, created_at;`
+	err := r.db.QueryRowContext(ctx, query, sub.URL, sub.Secret, pq.Array(sub.EventTypes)).
+		Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+func (r *PostgresWebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := `SELECT subscription_id, url, secret, event_types, created_at
+FROM webhook_subscriptions
+ORDER BY subscription_id;`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []domain.WebhookSubscription
+	for rows.Next() {
+		var s domain.WebhookSubscription
+		err = rows.Scan(&s.ID, &s.URL, &s.Secret, (*pq.StringArray)(&s.EventTypes), &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+func (r *PostgresWebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE subscription_id = $1;`
+	res, err := r.db.ExecContext(ctx, query, subscriptionID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// EnqueueDeliveries queues payload for every subscription interested in
+// the event and reports how many deliveries were created. Publishing the
+// same event again creates no new deliveries.
+func (r *PostgresWebhookRepository) EnqueueDeliveries(ctx context.Context, event domain.Event, payload []byte) (int, error) {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at)
+SELECT subscription_id, $1, $2, $3, $4
+FROM webhook_subscriptions
+WHERE cardinality(event_types) = 0 OR $2 = ANY(event_types)
+ON CONFLICT (subscription_id, event_id) WHERE redelivery_of IS NULL DO NOTHING;`
+	res, err := r.db.ExecContext(ctx, query, event.ID, event.Type, payload, event.OccurredAt)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries that are due
+// and pushes their next attempt lease into the future, so that another
+// dispatcher does not pick them up while they are being sent.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	query := `WITH due AS (
+    SELECT delivery_id
+    FROM webhook_deliveries
+    WHERE status = 'PENDING'
+      AND next_attempt_at <= $1
+    ORDER BY next_attempt_at
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = $2
+FROM due, webhook_subscriptions s
+WHERE d.delivery_id = due.delivery_id
+  AND s.subscription_id = d.subscription_id
+RETURNING ` + deliveryColumns + `, s.url, s.secret;`
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt.
+func (r *PostgresWebhookRepository) RecordAttempt(ctx context.Context, d domain.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_attempt_at = $5,
+    last_status_code = $6,
+    last_error = $7,
+    delivered_at = $8
+WHERE delivery_id = $1;`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		d.ID,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		d.LastStatusCode,
+		d.LastError,
+		d.DeliveredAt,
+	)
+	return err
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(
+	ctx context.Context,
+	filter domain.DeliveryFilter,
+) ([]domain.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+FROM webhook_deliveries d
+WHERE ($1 = 0 OR d.subscription_id = $1)
+  AND ($2 = '' OR d.status = $2)
+  AND ($3 = 0 OR d.delivery_id < $3)
+ORDER BY d.delivery_id DESC
+LIMIT $4;`
+	rows, err := r.db.QueryContext(ctx, query, filter.SubscriptionID, filter.Status, filter.BeforeID, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues a new delivery with the payload of an existing one.
+func (r *PostgresWebhookRepository) Redeliver(ctx context.Context, deliveryID int64) (domain.WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries AS d (subscription_id, event_id, event_type, payload, redelivery_of)
+SELECT subscription_id, event_id, event_type, payload, delivery_id
+FROM webhook_deliveries
+WHERE delivery_id = $1
+RETURNING ` + deliveryColumns + `;`
+	return scanDelivery(r.db.QueryRowContext(ctx, query, deliveryID), false)
+}
+
+// scanDelivery reads deliveryColumns, followed by the subscription url
+// and secret when withTarget is set.
+func scanDelivery(row rowScanner, withTarget bool) (domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var lastAttemptAt, deliveredAt sql.NullTime
+	var lastStatusCode, redeliveryOf sql.NullInt64
+	dest := []any{
+		&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &lastAttemptAt, &lastStatusCode, &d.LastError, &deliveredAt,
+		&redeliveryOf, &d.CreatedAt,
+	}
+	if withTarget {
+		dest = append(dest, &d.URL, &d.Secret)
+	}
+	err := row.Scan(dest...)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if lastAttemptAt.Valid {
+		d.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if lastStatusCode.Valid {
+		v := int(lastStatusCode.Int64)
+		d.LastStatusCode = &v
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	if redeliveryOf.Valid {
+		d.RedeliveryOf = &redeliveryOf.Int64
+	}
+	return d, nil
+}
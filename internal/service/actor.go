@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor attaches the identity of whoever performs the request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or "" when unknown.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
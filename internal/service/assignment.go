@@ -0,0 +1,296 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+const (
+	ReasonCodeOwner    = "code_owner"
+	ReasonTagMatch     = "tag_match"
+	ReasonTeamMember   = "team_member"
+	ReasonFallbackTeam = "fallback_team"
+	ReasonOverCapacity = "over_capacity"
+)
+
+type AssignedReviewer struct {
+	User         domain.User
+	FallbackTeam string
+	Strategy     string
+	Reason       string
+}
+
+func (r AssignedReviewer) FromFallback() bool {
+	return r.FallbackTeam != ""
+}
+
+func (r AssignedReviewer) record(pool []string, replacedUserID string, at time.Time) domain.ReviewerAssignment {
+	return domain.ReviewerAssignment{
+		UserID:         r.User.ID,
+		Strategy:       r.Strategy,
+		Reason:         r.Reason,
+		FallbackTeam:   r.FallbackTeam,
+		CandidatePool:  pool,
+		ReplacedUserID: replacedUserID,
+		AssignedAt:     at,
+	}
+}
+
+type Assignment struct {
+	MinReviewers  int
+	MaxReviewers  int
+	Reviewers     []AssignedReviewer
+	CandidatePool []string
+	Seed          *int64
+}
+
+func (a Assignment) records(at time.Time) []domain.ReviewerAssignment {
+	records := make([]domain.ReviewerAssignment, 0, len(a.Reviewers))
+	for _, r := range a.Reviewers {
+		records = append(records, r.record(a.CandidatePool, "", at))
+	}
+	return records
+}
+
+func (a Assignment) Understaffed() bool {
+	return len(a.Reviewers) < a.MinReviewers
+}
+
+func (a Assignment) IDsWithReason(reason string) []string {
+	ids := make([]string, 0)
+	for _, r := range a.Reviewers {
+		if r.Reason == reason {
+			ids = append(ids, r.User.ID)
+		}
+	}
+	return ids
+}
+
+// preferenceTier is a group of users that should be picked before
+// the rest of a team, e.g. the code owners of the changed paths.
+// A nil Match accepts every member.
+type preferenceTier struct {
+	Match  func(u domain.User) bool
+	Reason string
+}
+
+type selectionOptions struct {
+	Seed   *int64
+	Rand   *rand.Rand
+	DryRun bool
+}
+
+func (o selectionOptions) withRand() selectionOptions {
+	if o.Seed != nil && o.Rand == nil {
+		o.Rand = rand.New(rand.NewSource(*o.Seed))
+	}
+	return o
+}
+
+type reviewerQuery struct {
+	HomeTeam      string
+	FallbackTeams []string
+	Exclude       []string
+	Preferred     []preferenceTier
+	Count         int
+	// Required is the number of reviewers below which the REJECT
+	// capacity policy fails the assignment.
+	Required       int
+	CapacityPolicy string
+	Options        selectionOptions
+}
+
+// pickReviewers fills up to Count slots from the home team first and then
+// from the fallback teams in priority order. Within every team preferred
+// tiers are exhausted before ordinary members. Excluded users are never picked,
+// members at capacity only according to CapacityPolicy.
+func (s *PrService) pickReviewers(ctx context.Context, q reviewerQuery) ([]AssignedReviewer, []string, error) {
+	var picked []AssignedReviewer
+	var pool []string
+	var saturated []domain.User
+	saturatedTeams := make(map[string]string)
+	visited := make([]string, 0, len(q.FallbackTeams)+1)
+	excluded := slices.Clone(q.Exclude)
+
+	for _, team := range append([]string{q.HomeTeam}, q.FallbackTeams...) {
+		if len(picked) >= q.Count {
+			break
+		}
+		if slices.Contains(visited, team) {
+			continue
+		}
+		visited = append(visited, team)
+
+		members, err := s.URepository.GetActiveTeamMembersExcept(ctx, team, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, m := range members {
+			if !slices.Contains(excluded, m.ID) {
+				pool = append(pool, m.ID)
+			}
+		}
+		members, full, err := s.splitByCapacity(ctx, team, members)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, u := range full {
+			if !slices.Contains(excluded, u.ID) {
+				saturated = append(saturated, u)
+				saturatedTeams[u.ID] = team
+			}
+		}
+
+		defaultReason := ReasonTeamMember
+		if team != q.HomeTeam {
+			defaultReason = ReasonFallbackTeam
+		}
+
+		tiers := append(slices.Clone(q.Preferred), preferenceTier{Reason: defaultReason})
+		for _, tier := range tiers {
+			if len(picked) >= q.Count {
+				break
+			}
+
+			var candidates []domain.User
+			for _, m := range members {
+				if slices.Contains(excluded, m.ID) {
+					continue
+				}
+				if tier.Match != nil && !tier.Match(m) {
+					continue
+				}
+				candidates = append(candidates, m)
+			}
+
+			selected, err := s.selectFrom(ctx, team, candidates, q.Count-len(picked), q.Options)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, u := range selected {
+				picked = append(picked, s.newAssignedReviewer(u, team, q.HomeTeam, tier.Reason))
+				excluded = append(excluded, u.ID)
+			}
+		}
+	}
+
+	if len(picked) >= q.Count || len(saturated) == 0 {
+		return picked, pool, nil
+	}
+
+	switch q.CapacityPolicy {
+	case domain.CapacityReject:
+		if len(picked) < q.Required {
+			return nil, nil, ErrReviewersAtCapacity
+		}
+	case domain.CapacityAssignAnyway:
+		selected, err := s.selectFrom(ctx, q.HomeTeam, saturated, q.Count-len(picked), q.Options)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, u := range selected {
+			picked = append(picked, s.newAssignedReviewer(u, saturatedTeams[u.ID], q.HomeTeam, ReasonOverCapacity))
+		}
+	}
+
+	return picked, pool, nil
+}
+
+func (s *PrService) selectFrom(
+	ctx context.Context,
+	team string,
+	candidates []domain.User,
+	count int,
+	opts selectionOptions,
+) ([]domain.User, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return nil, nil
+	}
+	return s.Selector.Select(ctx, SelectionRequest{
+		TeamName:   team,
+		Candidates: candidates,
+		Count:      count,
+		Rand:       opts.Rand,
+		DryRun:     opts.DryRun,
+	})
+}
+
+// splitByCapacity separates members that can take another review
+// from those already at their open review limit.
+func (s *PrService) splitByCapacity(
+	ctx context.Context,
+	team string,
+	members []domain.User,
+) ([]domain.User, []domain.User, error) {
+	if len(members) == 0 {
+		return nil, nil, nil
+	}
+
+	settings, err := s.TRepository.GetSettings(ctx, team)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.ID)
+	}
+	loads, err := s.URepository.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var available, full []domain.User
+	for _, m := range members {
+		capacity := m.Capacity(settings.MaxOpenReviews)
+		if capacity != nil && loads[m.ID] >= *capacity {
+			full = append(full, m)
+			continue
+		}
+		available = append(available, m)
+	}
+
+	return available, full, nil
+}
+
+func (s *PrService) newAssignedReviewer(u domain.User, team, homeTeam, reason string) AssignedReviewer {
+	reviewer := AssignedReviewer{
+		User:     u,
+		Strategy: s.Selector.Strategy(team),
+		Reason:   reason,
+	}
+	if team != homeTeam {
+		reviewer.FallbackTeam = team
+	}
+	return reviewer
+}
+
+func (s *PrService) ownerTier(ctx context.Context, teamName string, changedFiles []string) (preferenceTier, error) {
+	var owners []string
+	if len(changedFiles) > 0 {
+		rules, err := s.TRepository.GetOwnershipRules(ctx, teamName)
+		if err != nil {
+			return preferenceTier{}, err
+		}
+		owners = domain.MatchOwners(rules, changedFiles)
+	}
+
+	return preferenceTier{
+		Match: func(u domain.User) bool {
+			return slices.Contains(owners, u.ID)
+		},
+		Reason: ReasonCodeOwner,
+	}, nil
+}
+
+func labelTier(labels []string) preferenceTier {
+	return preferenceTier{
+		Match: func(u domain.User) bool {
+			return u.HasAnyTag(labels)
+		},
+		Reason: ReasonTagMatch,
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeUserRepository serves team members and open review counts from
+// memory. Methods the tests do not need panic through the nil interface.
+type fakeUserRepository struct {
+	UserRepository
+	members map[string][]domain.User
+	loads   map[string]int
+}
+
+func (f *fakeUserRepository) GetActiveTeamMembersExcept(_ context.Context, teamName, excludeID string) ([]domain.User, error) {
+	var members []domain.User
+	for _, u := range f.members[teamName] {
+		if u.IsActive && u.ID != excludeID {
+			members = append(members, u)
+		}
+	}
+	return members, nil
+}
+
+func (f *fakeUserRepository) CountOpenReviews(_ context.Context, userIDs []string) (map[string]int, error) {
+	loads := make(map[string]int, len(userIDs))
+	for _, id := range userIDs {
+		if n, ok := f.loads[id]; ok {
+			loads[id] = n
+		}
+	}
+	return loads, nil
+}
+
+type fakeTeamRepository struct {
+	TeamRepository
+	settings map[string]domain.TeamSettings
+}
+
+func (f *fakeTeamRepository) GetSettings(_ context.Context, teamName string) (domain.TeamSettings, error) {
+	if s, ok := f.settings[teamName]; ok {
+		return s, nil
+	}
+	return domain.DefaultTeamSettings(teamName), nil
+}
+
+// orderedSelector picks candidates in the order they are offered, so that
+// tests see which tier and team a reviewer came from.
+type orderedSelector struct{}
+
+func (orderedSelector) Strategy(string) string {
+	return "ordered"
+}
+
+func (orderedSelector) Select(_ context.Context, req SelectionRequest) ([]domain.User, error) {
+	return req.Candidates[:min(req.Count, len(req.Candidates))], nil
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestPickReviewers(t *testing.T) {
+	member := func(id string, tags ...string) domain.User {
+		return domain.User{ID: id, Username: id, IsActive: true, Tags: tags}
+	}
+	members := map[string][]domain.User{
+		"backend":  {member("a1"), member("a2", "db"), member("a3"), member("a4", "db")},
+		"platform": {member("p1"), member("p2")},
+		"mobile":   {member("m1")},
+	}
+	owners := preferenceTier{
+		Match:  func(u domain.User) bool { return slices.Contains([]string{"a3", "p2"}, u.ID) },
+		Reason: ReasonCodeOwner,
+	}
+	limited := func(team string, policy string) map[string]domain.TeamSettings {
+		s := domain.DefaultTeamSettings(team)
+		s.MaxOpenReviews = intPtr(1)
+		s.CapacityPolicy = policy
+		return map[string]domain.TeamSettings{team: s}
+	}
+	busy := map[string]int{"a1": 1, "a2": 1, "a3": 0, "p1": 3}
+
+	tests := []struct {
+		name     string
+		query    reviewerQuery
+		settings map[string]domain.TeamSettings
+		loads    map[string]int
+		want     []string
+		wantPool []string
+		wantErr  error
+	}{
+		{
+			name:     "team members in selector order",
+			query:    reviewerQuery{HomeTeam: "backend", Count: 2},
+			want:     []string{"a1 team_member", "a2 team_member"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name: "owners then tags then team",
+			query: reviewerQuery{
+				HomeTeam:  "backend",
+				Preferred: []preferenceTier{owners, labelTier([]string{"db"})},
+				Count:     4,
+			},
+			want:     []string{"a3 code_owner", "a2 tag_match", "a4 tag_match", "a1 team_member"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name: "fallback teams after the home team, tiers apply there too",
+			query: reviewerQuery{
+				HomeTeam:      "backend",
+				FallbackTeams: []string{"platform", "mobile"},
+				Exclude:       []string{"a1", "a2"},
+				Preferred:     []preferenceTier{owners},
+				Count:         5,
+			},
+			want: []string{
+				"a3 code_owner", "a4 team_member",
+				"p2 code_owner platform", "p1 fallback_team platform",
+				"m1 fallback_team mobile",
+			},
+			wantPool: []string{"a3", "a4", "p1", "p2", "m1"},
+		},
+		{
+			name: "fallback teams are not visited once the slots are filled",
+			query: reviewerQuery{
+				HomeTeam:      "backend",
+				FallbackTeams: []string{"platform", "backend"},
+				Count:         4,
+			},
+			want:     []string{"a1 team_member", "a2 team_member", "a3 team_member", "a4 team_member"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name:     "excluded users are never picked",
+			query:    reviewerQuery{HomeTeam: "backend", Exclude: []string{"a1", "a3"}, Count: 3},
+			want:     []string{"a2 team_member", "a4 team_member"},
+			wantPool: []string{"a2", "a4"},
+		},
+		{
+			name:     "assign fewer skips members at capacity",
+			query:    reviewerQuery{HomeTeam: "backend", Count: 3, Required: 3, CapacityPolicy: domain.CapacityAssignFewer},
+			settings: limited("backend", domain.CapacityAssignFewer),
+			loads:    busy,
+			want:     []string{"a3 team_member", "a4 team_member"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name:     "assign anyway tops up from members at capacity",
+			query:    reviewerQuery{HomeTeam: "backend", Count: 3, Required: 3, CapacityPolicy: domain.CapacityAssignAnyway},
+			settings: limited("backend", domain.CapacityAssignAnyway),
+			loads:    busy,
+			want:     []string{"a3 team_member", "a4 team_member", "a1 over_capacity"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name: "assign anyway keeps the fallback team of a member at capacity",
+			query: reviewerQuery{
+				HomeTeam:       "mobile",
+				FallbackTeams:  []string{"platform"},
+				Exclude:        []string{"m1", "p2"},
+				Count:          1,
+				Required:       1,
+				CapacityPolicy: domain.CapacityAssignAnyway,
+			},
+			settings: limited("platform", domain.CapacityAssignAnyway),
+			loads:    busy,
+			want:     []string{"p1 over_capacity platform"},
+			wantPool: []string{"p1"},
+		},
+		{
+			name:     "reject fails below the required count",
+			query:    reviewerQuery{HomeTeam: "backend", Count: 3, Required: 3, CapacityPolicy: domain.CapacityReject},
+			settings: limited("backend", domain.CapacityReject),
+			loads:    busy,
+			wantErr:  ErrReviewersAtCapacity,
+		},
+		{
+			name:     "reject accepts fewer reviewers than slots when enough are required",
+			query:    reviewerQuery{HomeTeam: "backend", Count: 3, Required: 2, CapacityPolicy: domain.CapacityReject},
+			settings: limited("backend", domain.CapacityReject),
+			loads:    busy,
+			want:     []string{"a3 team_member", "a4 team_member"},
+			wantPool: []string{"a1", "a2", "a3", "a4"},
+		},
+		{
+			name:     "reject does not fail when nobody is at capacity",
+			query:    reviewerQuery{HomeTeam: "mobile", Count: 2, Required: 2, CapacityPolicy: domain.CapacityReject},
+			settings: limited("mobile", domain.CapacityReject),
+			want:     []string{"m1 team_member"},
+			wantPool: []string{"m1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PrService{
+				URepository: &fakeUserRepository{members: members, loads: tt.loads},
+				TRepository: &fakeTeamRepository{settings: tt.settings},
+				Selector:    orderedSelector{},
+			}
+
+			picked, pool, err := s.pickReviewers(context.Background(), tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("pickReviewers() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got := make([]string, 0, len(picked))
+			for _, r := range picked {
+				desc := r.User.ID + " " + r.Reason
+				if r.FromFallback() {
+					desc += " " + r.FallbackTeam
+				}
+				got = append(got, desc)
+				if r.Strategy != "ordered" {
+					t.Errorf("reviewer %s strategy = %q, want the selector's", r.User.ID, r.Strategy)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pickReviewers() = %q, want %q", got, tt.want)
+			}
+			if !slices.Equal(pool, tt.wantPool) {
+				t.Errorf("candidate pool = %v, want %v", pool, tt.wantPool)
+			}
+		})
+	}
+}
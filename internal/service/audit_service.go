@@ -0,0 +1,146 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+const (
+	AuditTeamCreate        = "team.create"
+	AuditTeamSettings      = "team.settings_update"
+	AuditTeamCodeowners    = "team.codeowners_update"
+	AuditTeamMemberAdd     = "team.member_add"
+	AuditTeamMemberUpdate  = "team.member_update"
+	AuditTeamMemberRemove  = "team.member_remove"
+	AuditUserUpsert        = "user.upsert"
+	AuditUserSetActive     = "user.set_active"
+	AuditUserTags          = "user.tags_update"
+	AuditUserCapacity      = "user.capacity_update"
+	AuditUserTeamMove      = "user.team_move"
+	AuditUserAbsenceCreate = "user.absence_create"
+	AuditUserAbsenceCancel = "user.absence_cancel"
+	AuditPrCreate          = "pr.create"
+	AuditPrReady           = "pr.ready"
+	AuditPrReview          = "pr.review"
+	AuditPrMerge           = "pr.merge"
+	AuditPrMergeOverride   = "pr.merge_override"
+	AuditPrClose           = "pr.close"
+	AuditPrReopen          = "pr.reopen"
+	AuditPrReassign        = "pr.reassign"
+	AuditPrEscalate        = "pr.escalate"
+)
+
+const (
+	AuditEntityTeam        = "team"
+	AuditEntityUser        = "user"
+	AuditEntityPullRequest = "pull_request"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+type AuditRepository interface {
+	ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+type AuditService struct {
+	ARepository AuditRepository
+}
+
+// AuditChange is one entity changed by an operation.
+type AuditChange struct {
+	EntityID string
+	Before   any
+	After    any
+}
+
+// NewAuditEntries describes changes made by one operation, attributed to
+// the actor in ctx. Repositories write them in the transaction of the
+// change itself, so an entry exists exactly when the change does.
+func NewAuditEntries(ctx context.Context, action, entityType string, changes ...AuditChange) ([]domain.AuditEntry, error) {
+	now := time.Now()
+	actor := ActorFrom(ctx)
+	entries := make([]domain.AuditEntry, 0, len(changes))
+	for _, c := range changes {
+		entry := domain.AuditEntry{
+			OccurredAt: now,
+			Actor:      actor,
+			Action:     action,
+			EntityType: entityType,
+			EntityID:   c.EntityID,
+		}
+
+		var err error
+		entry.Before, err = auditPayload(c.Before)
+		if err != nil {
+			return nil, fmt.Errorf("audit %s %s/%s: %w", action, entityType, c.EntityID, err)
+		}
+		entry.After, err = auditPayload(c.After)
+		if err != nil {
+			return nil, fmt.Errorf("audit %s %s/%s: %w", action, entityType, c.EntityID, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func auditPayload(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// List returns one page of entries and the cursor for the next page,
+// which is 0 when there are no more entries.
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, int64, error) {
+	if filter.Limit < 0 || filter.Limit > maxAuditPageSize || filter.BeforeID < 0 {
+		return nil, 0, ErrInvalidAuditFilter
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, ErrInvalidAuditFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+
+	entries, err := s.ARepository.ListAudit(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var next int64
+	if len(entries) == filter.Limit {
+		next = entries[len(entries)-1].ID
+	}
+	return entries, next, nil
+}
+
+// Export walks every entry matching the filter, newest first, page by page.
+func (s *AuditService) Export(ctx context.Context, filter domain.AuditFilter, emit func(domain.AuditEntry) error) error {
+	filter.Limit = maxAuditPageSize
+	for {
+		entries, next, err := s.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			err = emit(e)
+			if err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		filter.BeforeID = next
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrInvalidDeactivation = errors.New("invalid deactivation request")
+
+// maxDeactivationUsers bounds an explicit user list, which keeps a single
+// deactivation transaction short.
+const maxDeactivationUsers = 1000
+
+// DeactivationReport describes a completed deactivation: the users as
+// they are now and what happened to each of their open reviews.
+type DeactivationReport struct {
+	Users     []domain.User
+	Handovers []domain.ReviewHandover
+	// AuthoredPullRequestIDs are the users' own OPEN and DRAFT pull
+	// requests, which stay open for someone to pick up or close.
+	AuthoredPullRequestIDs []string
+}
+
+// Deactivate deactivates the target users and hands every review they hold
+// on an OPEN pull request over to the least loaded eligible member of the
+// author's team, the same home team Reassign uses, all in one transaction.
+// With AnyTeam set, the author team's fallback teams and then everyone else
+// are tried when the author's team has nobody left. A review nobody can take
+// over is left unassigned and reported as such.
+func (s *UserService) Deactivate(ctx context.Context, target domain.DeactivationTarget) (DeactivationReport, error) {
+	if (target.TeamName == "" && len(target.UserIDs) == 0) || len(target.UserIDs) > maxDeactivationUsers {
+		return DeactivationReport{}, ErrInvalidDeactivation
+	}
+
+	if target.TeamName != "" {
+		ok, err := s.TRepository.TeamExists(ctx, target.TeamName)
+		if err != nil {
+			return DeactivationReport{}, err
+		}
+		if !ok {
+			return DeactivationReport{}, ErrTeamNotFound
+		}
+	}
+
+	// ids stays non-nil so that a team-only target reaches the repository
+	// as an empty array rather than NULL.
+	ids := []string{}
+	for _, id := range target.UserIDs {
+		if id == "" {
+			return DeactivationReport{}, ErrInvalidDeactivation
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	target.UserIDs = ids
+
+	var authored []string
+	var handovers []domain.ReviewHandover
+	users, err := s.URepository.DeactivateUsers(
+		ctx,
+		target,
+		func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error) {
+			authored = snapshot.AuthoredPullRequestIDs
+			var err error
+			handovers, err = s.planHandovers(ctx, snapshot, target.AnyTeam, time.Now())
+			return handovers, err
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DeactivationReport{}, ErrUserNotFound
+	}
+	if err != nil {
+		return DeactivationReport{}, err
+	}
+
+	return DeactivationReport{Users: users, Handovers: handovers, AuthoredPullRequestIDs: authored}, nil
+}
+
+// planHandovers picks a successor for every slot. Candidates come from the
+// author's team and, with anyTeam, from the author team's
+// fallback teams in priority order and then from every other team. The
+// least loaded candidate under capacity in the first team that has one
+// wins; members at capacity are only used when the author's team allows
+// it. Loads are tracked across slots so that one deactivation does not
+// pile every review onto the same user.
+func (s *UserService) planHandovers(
+	ctx context.Context,
+	snapshot domain.DeactivationSnapshot,
+	anyTeam bool,
+	now time.Time,
+) ([]domain.ReviewHandover, error) {
+	teams := make(map[string][]*domain.ReviewerCandidate)
+	var teamNames []string
+	for i := range snapshot.Candidates {
+		c := &snapshot.Candidates[i]
+		if _, ok := teams[c.User.TeamName]; !ok {
+			teamNames = append(teamNames, c.User.TeamName)
+		}
+		teams[c.User.TeamName] = append(teams[c.User.TeamName], c)
+	}
+
+	settings := make(map[string]domain.TeamSettings)
+	reviewers := make(map[string][]string, len(snapshot.Slots))
+	handovers := make([]domain.ReviewHandover, 0, len(snapshot.Slots))
+
+	for _, slot := range snapshot.Slots {
+		current, ok := reviewers[slot.PullRequestID]
+		if !ok {
+			current = slices.Clone(slot.ReviewerIDs)
+		}
+
+		authorSettings, ok := settings[slot.AuthorTeam]
+		if !ok {
+			var err error
+			authorSettings, err = s.TRepository.GetSettings(ctx, slot.AuthorTeam)
+			if err != nil {
+				return nil, err
+			}
+			settings[slot.AuthorTeam] = authorSettings
+		}
+
+		order := []string{slot.AuthorTeam}
+		if anyTeam {
+			order = append(order, authorSettings.FallbackTeams...)
+			order = append(order, teamNames...)
+		}
+
+		var tiers [][]*domain.ReviewerCandidate
+		var pool []string
+		visited := make([]string, 0, len(order))
+		for _, team := range order {
+			if slices.Contains(visited, team) {
+				continue
+			}
+			visited = append(visited, team)
+
+			var eligible []*domain.ReviewerCandidate
+			for _, c := range teams[team] {
+				if c.User.ID == slot.AuthorID || slices.Contains(current, c.User.ID) {
+					continue
+				}
+				eligible = append(eligible, c)
+				pool = append(pool, c.User.ID)
+			}
+			tiers = append(tiers, eligible)
+		}
+
+		successor, reason := pickSuccessor(tiers, authorSettings.CapacityPolicy == domain.CapacityAssignAnyway)
+
+		handover := domain.ReviewHandover{PullRequestID: slot.PullRequestID, FromUserID: slot.ReviewerID}
+		if successor != nil {
+			successor.OpenReviews++
+			handover.To = domain.ReviewerAssignment{
+				UserID:         successor.User.ID,
+				Strategy:       StrategyLeastLoaded,
+				Reason:         reason,
+				CandidatePool:  pool,
+				ReplacedUserID: slot.ReviewerID,
+				AssignedAt:     now,
+			}
+			if successor.User.TeamName != slot.AuthorTeam {
+				handover.To.FallbackTeam = successor.User.TeamName
+				if reason == ReasonTeamMember {
+					handover.To.Reason = ReasonFallbackTeam
+				}
+			}
+		}
+		handovers = append(handovers, handover)
+
+		current = slices.DeleteFunc(current, func(id string) bool { return id == slot.ReviewerID })
+		if successor != nil {
+			current = append(current, successor.User.ID)
+		}
+		reviewers[slot.PullRequestID] = current
+	}
+
+	return handovers, nil
+}
+
+// pickSuccessor walks the tiers in order and returns the least loaded
+// candidate under capacity, falling back to the least loaded one at
+// capacity from the earliest tier when overCapacity is allowed.
+func pickSuccessor(tiers [][]*domain.ReviewerCandidate, overCapacity bool) (*domain.ReviewerCandidate, string) {
+	for _, tier := range tiers {
+		if c, reason := leastLoadedCandidate(tier, false); c != nil {
+			return c, reason
+		}
+	}
+	if !overCapacity {
+		return nil, ""
+	}
+	for _, tier := range tiers {
+		if c, reason := leastLoadedCandidate(tier, true); c != nil {
+			return c, reason
+		}
+	}
+	return nil, ""
+}
+
+// leastLoadedCandidate returns the candidate with the fewest open reviews,
+// preferring those under capacity. Ties go to the lowest user id.
+func leastLoadedCandidate(candidates []*domain.ReviewerCandidate, overCapacity bool) (*domain.ReviewerCandidate, string) {
+	var best, bestFull *domain.ReviewerCandidate
+	less := func(a, b *domain.ReviewerCandidate) bool {
+		return b == nil || cmp.Or(cmp.Compare(a.OpenReviews, b.OpenReviews), cmp.Compare(a.User.ID, b.User.ID)) < 0
+	}
+	for _, c := range candidates {
+		if c.AtCapacity() {
+			if less(c, bestFull) {
+				bestFull = c
+			}
+			continue
+		}
+		if less(c, best) {
+			best = c
+		}
+	}
+
+	if best != nil {
+		return best, ReasonTeamMember
+	}
+	if overCapacity && bestFull != nil {
+		return bestFull, ReasonOverCapacity
+	}
+	return nil, ""
+}
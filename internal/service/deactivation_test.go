@@ -0,0 +1,286 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func candidate(id, team string, open int, capacity *int) domain.ReviewerCandidate {
+	return domain.ReviewerCandidate{
+		User:        domain.User{ID: id, Username: id, TeamName: team, IsActive: true},
+		OpenReviews: open,
+		Capacity:    capacity,
+	}
+}
+
+func candidatePtrs(cs ...domain.ReviewerCandidate) []*domain.ReviewerCandidate {
+	ptrs := make([]*domain.ReviewerCandidate, 0, len(cs))
+	for i := range cs {
+		ptrs = append(ptrs, &cs[i])
+	}
+	return ptrs
+}
+
+func candidateID(c *domain.ReviewerCandidate) string {
+	if c == nil {
+		return ""
+	}
+	return c.User.ID
+}
+
+func TestLeastLoadedCandidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		candidates   []domain.ReviewerCandidate
+		overCapacity bool
+		want         string
+		wantReason   string
+	}{
+		{"no candidates", nil, true, "", ""},
+		{"fewest open reviews", []domain.ReviewerCandidate{
+			candidate("u1", "backend", 3, nil),
+			candidate("u2", "backend", 1, nil),
+			candidate("u3", "backend", 2, nil),
+		}, false, "u2", ReasonTeamMember},
+		{"tie goes to the lowest id", []domain.ReviewerCandidate{
+			candidate("u3", "backend", 1, nil),
+			candidate("u2", "backend", 1, nil),
+		}, false, "u2", ReasonTeamMember},
+		{"members at capacity are skipped", []domain.ReviewerCandidate{
+			candidate("u1", "backend", 0, intPtr(0)),
+			candidate("u2", "backend", 4, intPtr(5)),
+		}, true, "u2", ReasonTeamMember},
+		{"everyone at capacity", []domain.ReviewerCandidate{
+			candidate("u1", "backend", 2, intPtr(2)),
+		}, false, "", ""},
+		{"least loaded at capacity when allowed", []domain.ReviewerCandidate{
+			candidate("u1", "backend", 3, intPtr(2)),
+			candidate("u2", "backend", 2, intPtr(2)),
+		}, true, "u2", ReasonOverCapacity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := leastLoadedCandidate(candidatePtrs(tt.candidates...), tt.overCapacity)
+			if candidateID(got) != tt.want || reason != tt.wantReason {
+				t.Errorf("leastLoadedCandidate() = %q %q, want %q %q", candidateID(got), reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPickSuccessor(t *testing.T) {
+	home := []domain.ReviewerCandidate{
+		candidate("h1", "backend", 5, nil),
+		candidate("h2", "backend", 2, intPtr(2)),
+	}
+	full := []domain.ReviewerCandidate{
+		candidate("h1", "backend", 3, intPtr(3)),
+		candidate("h2", "backend", 2, intPtr(2)),
+	}
+	fallback := []domain.ReviewerCandidate{
+		candidate("f1", "platform", 0, nil),
+	}
+	fallbackFull := []domain.ReviewerCandidate{
+		candidate("f1", "platform", 0, intPtr(0)),
+	}
+
+	tests := []struct {
+		name         string
+		tiers        [][]domain.ReviewerCandidate
+		overCapacity bool
+		want         string
+		wantReason   string
+	}{
+		{"earlier tier wins over a less loaded later one", [][]domain.ReviewerCandidate{home, fallback}, false, "h1", ReasonTeamMember},
+		{"later tier when the earlier one is full", [][]domain.ReviewerCandidate{full, fallback}, true, "f1", ReasonTeamMember},
+		{"empty tiers are skipped", [][]domain.ReviewerCandidate{nil, fallback}, false, "f1", ReasonTeamMember},
+		{"everyone full", [][]domain.ReviewerCandidate{full, fallbackFull}, false, "", ""},
+		{"over capacity from the earliest tier", [][]domain.ReviewerCandidate{full, fallbackFull}, true, "h2", ReasonOverCapacity},
+		{"no tiers", nil, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiers := make([][]*domain.ReviewerCandidate, 0, len(tt.tiers))
+			for _, tier := range tt.tiers {
+				tiers = append(tiers, candidatePtrs(slices.Clone(tier)...))
+			}
+			got, reason := pickSuccessor(tiers, tt.overCapacity)
+			if candidateID(got) != tt.want || reason != tt.wantReason {
+				t.Errorf("pickSuccessor() = %q %q, want %q %q", candidateID(got), reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPlanHandovers(t *testing.T) {
+	slot := func(prID, authorID, authorTeam, reviewerID string, reviewers ...string) domain.OpenReviewSlot {
+		return domain.OpenReviewSlot{
+			PullRequestID: prID,
+			AuthorID:      authorID,
+			AuthorTeam:    authorTeam,
+			ReviewerID:    reviewerID,
+			ReviewerIDs:   append([]string{reviewerID}, reviewers...),
+		}
+	}
+	settings := func(team, policy string, fallbacks ...string) domain.TeamSettings {
+		s := domain.DefaultTeamSettings(team)
+		s.CapacityPolicy = policy
+		s.FallbackTeams = fallbacks
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		slots      []domain.OpenReviewSlot
+		candidates []domain.ReviewerCandidate
+		settings   []domain.TeamSettings
+		anyTeam    bool
+		want       []string
+	}{
+		{
+			name: "loads are tracked across slots",
+			slots: []domain.OpenReviewSlot{
+				slot("pr1", "a", "backend", "x"),
+				slot("pr2", "a", "backend", "x"),
+				slot("pr3", "a", "backend", "x"),
+			},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 0, nil),
+				candidate("b2", "backend", 1, nil),
+			},
+			want: []string{"pr1 x->b1 team_member", "pr2 x->b1 team_member", "pr3 x->b2 team_member"},
+		},
+		{
+			name: "handed over within the author's team",
+			slots: []domain.OpenReviewSlot{
+				slot("pr1", "m", "mobile", "x"),
+				slot("pr2", "a", "backend", "x"),
+			},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 0, nil),
+				candidate("m1", "mobile", 4, nil),
+			},
+			want: []string{"pr1 x->m1 team_member", "pr2 x->b1 team_member"},
+		},
+		{
+			name: "author and remaining reviewers are skipped",
+			slots: []domain.OpenReviewSlot{
+				slot("pr1", "b1", "backend", "x", "b2", "y"),
+				slot("pr1", "b1", "backend", "y", "x", "b2"),
+			},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 0, nil),
+				candidate("b2", "backend", 0, nil),
+				candidate("b3", "backend", 5, nil),
+				candidate("b4", "backend", 6, nil),
+			},
+			want: []string{"pr1 x->b3 team_member", "pr1 y->b4 team_member"},
+		},
+		{
+			name:  "left unassigned when everyone is at capacity",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 2, intPtr(2)),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignFewer)},
+			want:     []string{"pr1 x->"},
+		},
+		{
+			name:  "over capacity when the author's team assigns anyway",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 3, intPtr(2)),
+				candidate("b2", "backend", 2, intPtr(2)),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignAnyway)},
+			want:     []string{"pr1 x->b2 over_capacity"},
+		},
+		{
+			name:  "other teams are not used without AnyTeam",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("p1", "platform", 0, nil),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignFewer, "platform")},
+			want:     []string{"pr1 x->"},
+		},
+		{
+			name:  "AnyTeam tries fallback teams before the rest",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("m1", "mobile", 0, nil),
+				candidate("p1", "platform", 3, nil),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignFewer, "platform")},
+			anyTeam:  true,
+			want:     []string{"pr1 x->p1 fallback_team platform"},
+		},
+		{
+			name:  "AnyTeam falls through to every other team",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 1, intPtr(1)),
+				candidate("p1", "platform", 1, intPtr(1)),
+				candidate("m1", "mobile", 4, nil),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignFewer, "platform")},
+			anyTeam:  true,
+			want:     []string{"pr1 x->m1 fallback_team mobile"},
+		},
+		{
+			name:  "AnyTeam prefers the author's team at capacity over nobody",
+			slots: []domain.OpenReviewSlot{slot("pr1", "a", "backend", "x")},
+			candidates: []domain.ReviewerCandidate{
+				candidate("b1", "backend", 1, intPtr(1)),
+				candidate("p1", "platform", 1, intPtr(1)),
+			},
+			settings: []domain.TeamSettings{settings("backend", domain.CapacityAssignAnyway, "platform")},
+			anyTeam:  true,
+			want:     []string{"pr1 x->b1 over_capacity"},
+		},
+	}
+
+	now := time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamSettings := make(map[string]domain.TeamSettings, len(tt.settings))
+			for _, s := range tt.settings {
+				teamSettings[s.TeamName] = s
+			}
+			s := &UserService{TRepository: &fakeTeamRepository{settings: teamSettings}}
+			snapshot := domain.DeactivationSnapshot{
+				Slots:      tt.slots,
+				Candidates: slices.Clone(tt.candidates),
+			}
+
+			handovers, err := s.planHandovers(context.Background(), snapshot, tt.anyTeam, now)
+			if err != nil {
+				t.Fatalf("planHandovers: %v", err)
+			}
+
+			got := make([]string, 0, len(handovers))
+			for _, h := range handovers {
+				desc := fmt.Sprintf("%s %s->%s", h.PullRequestID, h.FromUserID, h.To.UserID)
+				if !h.Unassigned() {
+					desc += " " + h.To.Reason
+					if h.To.FallbackTeam != "" {
+						desc += " " + h.To.FallbackTeam
+					}
+					if h.To.ReplacedUserID != h.FromUserID || !h.To.AssignedAt.Equal(now) {
+						t.Errorf("handover %s: replaced %q at %s", desc, h.To.ReplacedUserID, h.To.AssignedAt)
+					}
+				}
+				got = append(got, desc)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("planHandovers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
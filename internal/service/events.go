@@ -0,0 +1,92 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// EventPublisher receives events written to the outbox by the repositories,
+// once the change they describe has been committed. An event may be
+// published more than once, so implementations should deduplicate by ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+type PrEventData struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Name          string   `json:"pull_request_name"`
+	AuthorID      string   `json:"author_id"`
+	Status        string   `json:"status"`
+	ReviewerIDs   []string `json:"assigned_reviewers"`
+}
+
+type ReviewerReassignedData struct {
+	PrEventData
+	OldReviewerID string `json:"old_reviewer_id"`
+	// NewReviewerID is empty when nobody could take over.
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+type ReviewSubmittedData struct {
+	PrEventData
+	ReviewerID string `json:"reviewer_id"`
+	Verdict    string `json:"verdict"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+type TeamMemberData struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type TeamCreatedData struct {
+	TeamName string           `json:"team_name"`
+	Members  []TeamMemberData `json:"members"`
+}
+
+type TeamMembershipData struct {
+	TeamName string         `json:"team_name"`
+	Member   TeamMemberData `json:"member"`
+}
+
+type UserActivationData struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type UserTeamChangedData struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	// FromTeamName is empty for a user who belonged to no team.
+	FromTeamName string `json:"from_team_name"`
+	ToTeamName   string `json:"to_team_name"`
+}
+
+// NewEvent builds an event of the given type with a fresh ID,
+// attributed to the actor of ctx.
+func NewEvent(ctx context.Context, eventType string, data any) (domain.Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	return domain.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Actor:      ActorFrom(ctx),
+		Data:       payload,
+	}, nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	defaultOutboxBatchSize = 100
+	// outboxLease is how long a claimed event is hidden from other
+	// dispatchers; an event that could not be published is retried after it.
+	outboxLease = 30 * time.Second
+)
+
+type OutboxRepository interface {
+	ClaimEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Event, error)
+	MarkPublished(ctx context.Context, eventID string, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, eventID string, reason string) error
+}
+
+// OutboxDispatcher publishes the events that repositories write to the
+// outbox together with the changes they describe. An event is marked
+// published only after Publisher accepted it, so every committed event is
+// published at least once, and events of rolled back changes never are.
+type OutboxDispatcher struct {
+	Repository OutboxRepository
+	Publisher  EventPublisher
+	Interval   time.Duration
+	BatchSize  int
+}
+
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.PublishDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("outbox: dispatch failed: %v", err)
+			}
+		}
+	}
+}
+
+// PublishDue publishes one batch of events in the order they occurred.
+func (d *OutboxDispatcher) PublishDue(ctx context.Context) error {
+	batch := d.BatchSize
+	if batch <= 0 {
+		batch = defaultOutboxBatchSize
+	}
+
+	events, err := d.Repository.ClaimEvents(ctx, time.Now(), outboxLease, batch)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		err = d.Publisher.Publish(ctx, event)
+		if err != nil {
+			log.Printf("outbox: failed to publish %s %s: %v", event.Type, event.ID, err)
+			err = d.Repository.MarkFailed(ctx, event.ID, err.Error())
+		} else {
+			err = d.Repository.MarkPublished(ctx, event.ID, time.Now())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
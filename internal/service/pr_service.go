@@ -5,17 +5,39 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"math/rand"
 	"slices"
 	"time"
 )
 
 var (
-	ErrPrNotFound        = errors.New("pull_request not found")
-	ErrPrAlreadyExists   = errors.New("pull_request already exists")
-	ErrPrAlreadyMerged   = errors.New("pull_request is already merged")
-	ErrUserIsNotReviewer = errors.New("user is not reviewer")
-	ErrNoCandidate       = errors.New("no candidate")
+	ErrPrNotFound          = errors.New("pull_request not found")
+	ErrPrAlreadyExists     = errors.New("pull_request already exists")
+	ErrPrAlreadyMerged     = errors.New("pull_request is already merged")
+	ErrPrClosed            = errors.New("pull_request is closed")
+	ErrPrDraft             = errors.New("pull_request is a draft")
+	ErrPrNotDraft          = errors.New("pull_request is not a draft")
+	ErrUserIsNotReviewer   = errors.New("user is not reviewer")
+	ErrNoCandidate         = errors.New("no candidate")
+	ErrReviewersAtCapacity = errors.New("all candidate reviewers are at capacity")
+	ErrApprovalRequired    = errors.New("pull_request does not satisfy the approval policy")
+	ErrInvalidVerdict      = errors.New("invalid review verdict")
+	ErrInvalidPrFilter     = errors.New("invalid pull_request filter")
+	ErrPrStatusConflict    = errors.New("pull_request status keeps changing concurrently")
+	ErrOverrideNotAllowed  = errors.New("actor is not allowed to override the approval policy")
+)
+
+// errStatusChanged reports that a status change lost a race with a
+// concurrent one and should be re-evaluated against the new status.
+var errStatusChanged = errors.New("pull_request status changed concurrently")
+
+const (
+	defaultPrPageSize = 50
+	maxPrPageSize     = 500
+	// maxTransitionAttempts bounds how often a status change is
+	// re-evaluated after losing a race with a concurrent one.
+	maxTransitionAttempts = 3
 )
 
 type PrRepository interface {
@@ -23,72 +45,314 @@ type PrRepository interface {
 	CreatePRWithReviewers(
 		ctx context.Context,
 		pr domain.PullRequest,
-		reviewerIDs []string,
+		reviewers []domain.ReviewerAssignment,
+		created domain.PrTransition,
 	) (domain.PullRequest, error)
 	GetPRWithReviewers(ctx context.Context, prID string) (domain.PullRequest, error)
-	MarkMerged(ctx context.Context, prID string, mergedAt time.Time) error
-	ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error
+	ListPRs(ctx context.Context, filter domain.PrFilter) ([]domain.PullRequest, error)
+	ApplyTransition(ctx context.Context, t domain.PrTransition) error
+	MarkReady(ctx context.Context, t domain.PrTransition, reviewers []domain.ReviewerAssignment) error
+	GetStatusHistory(ctx context.Context, prID string) ([]domain.PrTransition, error)
+	GetEscalations(ctx context.Context, prID string) ([]domain.Escalation, error)
+	ReplaceReviewer(
+		ctx context.Context,
+		prID, oldUserID string,
+		newReviewer domain.ReviewerAssignment,
+		check func(ctx context.Context, current domain.PullRequest) error,
+	) error
+	GetReviewerAssignments(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error)
+	GetPRSnapshot(ctx context.Context, prID string) (domain.PrSnapshot, error)
+	SubmitReview(ctx context.Context, review domain.Review) (domain.Review, error)
 }
 
 type PrService struct {
 	PrRepository PrRepository
 	URepository  UserRepository
+	TRepository  TeamRepository
+	Selector     ReviewerSelector
+	// OverrideActors may merge with force, skipping the approval policy.
+	// The actor comes from an unauthenticated header, so the list only
+	// guards against mistakes, not against impersonation.
+	OverrideActors []string
 }
 
-func (s *PrService) CreatePRWithReviewers(ctx context.Context, prID, prName, authorID string) (domain.PullRequest, error) {
-	ok, err := s.PrRepository.PRExists(ctx, prID)
+type CreatePrInput struct {
+	ID           string
+	Name         string
+	AuthorID     string
+	ChangedFiles []string
+	Labels       []string
+	// Draft registers the pull request without assigning reviewers
+	// until it is marked ready.
+	Draft bool
+	// Seed makes reviewer selection reproducible, so that a preview
+	// and the following create pick the same reviewers.
+	Seed *int64
+}
+
+// PrDetails is a pull request together with the users it refers to.
+type PrDetails struct {
+	PullRequest domain.PullRequest
+	Author      domain.User
+	Reviewers   []ReviewerDetails
+}
+
+type ReviewerDetails struct {
+	User domain.User
+	// AssignedAt is zero for assignments made before it was recorded.
+	AssignedAt time.Time
+	// Review is the latest verdict of the reviewer, nil if there is none.
+	Review *domain.Review
+}
+
+func (in CreatePrInput) pullRequest(now time.Time) domain.PullRequest {
+	status := domain.PrStatusOpen
+	if in.Draft {
+		status = domain.PrStatusDraft
+	}
+
+	return domain.PullRequest{
+		ID:           in.ID,
+		Name:         in.Name,
+		AuthorID:     in.AuthorID,
+		Status:       status,
+		CreatedAt:    now,
+		MergedAt:     nil,
+		ChangedFiles: in.ChangedFiles,
+		Labels:       domain.NormalizeTags(in.Labels),
+	}
+}
+
+func (s *PrService) CreatePRWithReviewers(
+	ctx context.Context,
+	input CreatePrInput,
+) (domain.PullRequest, Assignment, error) {
+	ok, err := s.PrRepository.PRExists(ctx, input.ID)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, Assignment{}, err
 	}
 	if ok {
-		return domain.PullRequest{}, ErrPrAlreadyExists
+		return domain.PullRequest{}, Assignment{}, ErrPrAlreadyExists
+	}
+
+	pr := input.pullRequest(time.Now())
+	if pr.Status == domain.PrStatusDraft {
+		ok, err = s.URepository.UserExists(ctx, pr.AuthorID)
+		if err != nil {
+			return domain.PullRequest{}, Assignment{}, err
+		}
+		if !ok {
+			return domain.PullRequest{}, Assignment{}, ErrUserNotFound
+		}
+
+		pullRequest, err := s.PrRepository.CreatePRWithReviewers(ctx, pr, nil, domain.InitialPrTransition(pr, ActorFrom(ctx)))
+		if err != nil {
+			return domain.PullRequest{}, Assignment{}, err
+		}
+		return pullRequest, Assignment{}, nil
 	}
 
-	author, err := s.URepository.GetUserByID(ctx, authorID)
+	assignment, err := s.planAssignment(ctx, pr, selectionOptions{Seed: input.Seed})
 	if err != nil {
-		return domain.PullRequest{}, err
+		return domain.PullRequest{}, Assignment{}, err
 	}
-	activeTeamMembers, err := s.URepository.GetActiveTeamMembersExcept(ctx, author.TeamName, authorID)
+
+	pullRequest, err := s.PrRepository.CreatePRWithReviewers(
+		ctx,
+		pr,
+		assignment.records(pr.CreatedAt),
+		domain.InitialPrTransition(pr, ActorFrom(ctx)),
+	)
 	if err != nil {
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	return pullRequest, assignment, nil
+}
+
+// PreviewAssignment runs the same selection as CreatePRWithReviewers without
+// writing anything. When no seed is given a random one is generated and
+// returned in the assignment so the caller can pass it on to create.
+func (s *PrService) PreviewAssignment(ctx context.Context, input CreatePrInput) (Assignment, error) {
+	if input.ID != "" {
+		ok, err := s.PrRepository.PRExists(ctx, input.ID)
+		if err != nil {
+			return Assignment{}, err
+		}
+		if ok {
+			return Assignment{}, ErrPrAlreadyExists
+		}
+	}
+
+	seed := rand.Int63()
+	if input.Seed != nil {
+		seed = *input.Seed
+	}
+
+	pr := input.pullRequest(time.Now())
+	return s.planAssignment(ctx, pr, selectionOptions{Seed: &seed, DryRun: true})
+}
+
+func (s *PrService) planAssignment(ctx context.Context, pr domain.PullRequest, opts selectionOptions) (Assignment, error) {
+	author, err := s.URepository.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Assignment{}, ErrUserNotFound
+		}
+		return Assignment{}, err
+	}
+	settings, err := s.TRepository.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	owners, err := s.ownerTier(ctx, author.TeamName, pr.ChangedFiles)
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	reviewers, pool, err := s.pickReviewers(ctx, reviewerQuery{
+		HomeTeam:       author.TeamName,
+		FallbackTeams:  settings.FallbackTeams,
+		Exclude:        []string{pr.AuthorID},
+		Preferred:      []preferenceTier{owners, labelTier(pr.Labels)},
+		Count:          settings.MaxReviewers,
+		Required:       settings.MinReviewers,
+		CapacityPolicy: settings.CapacityPolicy,
+		Options:        opts.withRand(),
+	})
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	return Assignment{
+		MinReviewers:  settings.MinReviewers,
+		MaxReviewers:  settings.MaxReviewers,
+		Reviewers:     reviewers,
+		CandidatePool: pool,
+		Seed:          opts.Seed,
+	}, nil
+}
+
+// Merge marks the pull request as merged once the author team's approval
+// policy is satisfied. force skips the policy check and is only accepted
+// from OverrideActors.
+func (s *PrService) Merge(ctx context.Context, prID string, mergedAt time.Time, force bool) (domain.PullRequest, error) {
+	if force && !slices.Contains(s.OverrideActors, ActorFrom(ctx)) {
+		return domain.PullRequest{}, ErrOverrideNotAllowed
+	}
+
+	return retryTransition(func() (domain.PullRequest, error) {
+		return s.merge(ctx, prID, mergedAt, force)
+	})
+}
+
+func (s *PrService) merge(ctx context.Context, prID string, mergedAt time.Time, force bool) (domain.PullRequest, error) {
+	pr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PullRequest{}, ErrPrNotFound
+		}
 		return domain.PullRequest{}, err
 	}
 
-	pr := domain.PullRequest{
-		ID:        prID,
-		Name:      prName,
-		AuthorID:  authorID,
-		Status:    "OPEN",
-		CreatedAt: time.Now(),
-		MergedAt:  nil,
+	if pr.Status == domain.PrStatusMerged {
+		return pr, nil
+	}
+
+	reason := "merged"
+	if force {
+		reason = "merged with admin override"
+	}
+	transition, err := domain.NewPrTransition(pr, domain.PrStatusMerged, ActorFrom(ctx), reason, mergedAt)
+	if err != nil {
+		return domain.PullRequest{}, statusError(pr.Status)
 	}
+	transition.AdminOverride = force
 
-	var reviewerIDs []string
-	activeMembersLen := len(activeTeamMembers)
-	if activeMembersLen > 0 {
-		if activeMembersLen > 1 {
-			first := rand.Intn(activeMembersLen)
-			second := rand.Intn(activeMembersLen)
-			for {
-				if second != first {
-					break
-				}
-				second = rand.Intn(activeMembersLen)
-			}
-			reviewerIDs = append(reviewerIDs, activeTeamMembers[first].ID, activeTeamMembers[second].ID)
-		} else {
-			reviewerIDs = append(reviewerIDs, activeTeamMembers[0].ID)
+	if !force {
+		author, err := s.URepository.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		settings, err := s.TRepository.GetSettings(ctx, author.TeamName)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		if !settings.ApprovalSatisfied(pr.ReviewersIDs, pr.Reviews) {
+			return domain.PullRequest{}, ErrApprovalRequired
 		}
 	}
 
-	pullRequest, err := s.PrRepository.CreatePRWithReviewers(ctx, pr, reviewerIDs)
+	err = s.PrRepository.ApplyTransition(ctx, transition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PullRequest{}, errStatusChanged
+	}
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
 
-	return pullRequest, nil
+	updatedPr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return updatedPr, nil
+}
+
+// Ready moves a draft pull request to OPEN and assigns reviewers against
+// the current team membership, using the changed files and labels given
+// at creation.
+func (s *PrService) Ready(ctx context.Context, prID string) (domain.PullRequest, Assignment, error) {
+	pr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PullRequest{}, Assignment{}, ErrPrNotFound
+		}
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	if pr.Status != domain.PrStatusDraft {
+		return domain.PullRequest{}, Assignment{}, ErrPrNotDraft
+	}
+
+	now := time.Now()
+	transition, err := domain.NewPrTransition(pr, domain.PrStatusOpen, ActorFrom(ctx), "marked ready", now)
+	if err != nil {
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	assignment, err := s.planAssignment(ctx, pr, selectionOptions{})
+	if err != nil {
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	err = s.PrRepository.MarkReady(ctx, transition, assignment.records(now))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The pull request left DRAFT concurrently.
+		return domain.PullRequest{}, Assignment{}, ErrPrNotDraft
+	}
+	if err != nil {
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	pullRequest, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, Assignment{}, err
+	}
+
+	return pullRequest, assignment, nil
 }
 
-func (s *PrService) Merge(ctx context.Context, prID string, mergedAt time.Time) (domain.PullRequest, error) {
+// Close abandons an open pull request. Reviewers and reviews are kept,
+// but a closed pull request no longer counts towards reviewer load.
+func (s *PrService) Close(ctx context.Context, prID, reason string, closedAt time.Time) (domain.PullRequest, error) {
+	return retryTransition(func() (domain.PullRequest, error) {
+		return s.close(ctx, prID, reason, closedAt)
+	})
+}
+
+func (s *PrService) close(ctx context.Context, prID, reason string, closedAt time.Time) (domain.PullRequest, error) {
 	pr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -97,11 +361,22 @@ func (s *PrService) Merge(ctx context.Context, prID string, mergedAt time.Time)
 		return domain.PullRequest{}, err
 	}
 
-	if pr.Status == "MERGED" {
+	if pr.Status == domain.PrStatusClosed {
 		return pr, nil
 	}
 
-	err = s.PrRepository.MarkMerged(ctx, prID, mergedAt)
+	if reason == "" {
+		reason = "closed"
+	}
+	transition, err := domain.NewPrTransition(pr, domain.PrStatusClosed, ActorFrom(ctx), reason, closedAt)
+	if err != nil {
+		return domain.PullRequest{}, statusError(pr.Status)
+	}
+
+	err = s.PrRepository.ApplyTransition(ctx, transition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PullRequest{}, errStatusChanged
+	}
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -114,54 +389,307 @@ func (s *PrService) Merge(ctx context.Context, prID string, mergedAt time.Time)
 	return updatedPr, nil
 }
 
-func (s *PrService) Reassign(ctx context.Context, prID, oldRevId string) (domain.PullRequest, string, error) {
+func (s *PrService) Reopen(ctx context.Context, prID, reason string) (domain.PullRequest, error) {
+	return retryTransition(func() (domain.PullRequest, error) {
+		return s.reopen(ctx, prID, reason)
+	})
+}
+
+func (s *PrService) reopen(ctx context.Context, prID, reason string) (domain.PullRequest, error) {
 	pr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return domain.PullRequest{}, "", ErrPrNotFound
+			return domain.PullRequest{}, ErrPrNotFound
 		}
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, err
 	}
 
-	if pr.Status == "MERGED" {
-		return domain.PullRequest{}, "", ErrPrAlreadyMerged
+	if pr.Status == domain.PrStatusOpen {
+		return pr, nil
+	}
+	// Drafts become OPEN only through Ready, which assigns reviewers.
+	if pr.Status != domain.PrStatusClosed {
+		return domain.PullRequest{}, statusError(pr.Status)
 	}
 
-	if !slices.Contains(pr.ReviewersIDs, oldRevId) {
-		return domain.PullRequest{}, "", ErrUserIsNotReviewer
+	if reason == "" {
+		reason = "reopened"
+	}
+	transition, err := domain.NewPrTransition(pr, domain.PrStatusOpen, ActorFrom(ctx), reason, time.Now())
+	if err != nil {
+		return domain.PullRequest{}, statusError(pr.Status)
 	}
 
-	oldRev, err := s.URepository.GetUserByID(ctx, oldRevId)
+	err = s.PrRepository.ApplyTransition(ctx, transition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PullRequest{}, errStatusChanged
+	}
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, err
 	}
-	activeTeamMembers, err := s.URepository.GetActiveTeamMembersExcept(ctx, oldRev.TeamName, oldRevId)
+
+	updatedPr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, err
 	}
 
-	var candidates []string
-	for _, m := range activeTeamMembers {
-		if (m.ID != oldRevId) && (m.ID != pr.AuthorID) &&
-			(!slices.Contains(pr.ReviewersIDs, m.ID)) {
-			candidates = append(candidates, m.ID)
+	return updatedPr, nil
+}
+
+// retryTransition runs a status change again while it loses races with
+// concurrent ones, giving up with ErrPrStatusConflict after
+// maxTransitionAttempts attempts.
+func retryTransition(change func() (domain.PullRequest, error)) (domain.PullRequest, error) {
+	for attempt := 1; ; attempt++ {
+		pr, err := change()
+		if !errors.Is(err, errStatusChanged) {
+			return pr, err
+		}
+		if attempt == maxTransitionAttempts {
+			return domain.PullRequest{}, ErrPrStatusConflict
 		}
 	}
-	if len(candidates) == 0 {
-		return domain.PullRequest{}, "", ErrNoCandidate
+}
+
+// statusError explains why a pull request in the given status
+// cannot be acted upon.
+func statusError(status domain.PrStatus) error {
+	switch status {
+	case domain.PrStatusMerged:
+		return ErrPrAlreadyMerged
+	case domain.PrStatusClosed:
+		return ErrPrClosed
+	case domain.PrStatusDraft:
+		return ErrPrDraft
 	}
+	return fmt.Errorf("unexpected pull_request status %q", status)
+}
 
-	newRevID := candidates[rand.Intn(len(candidates))]
+func (s *PrService) SubmitReview(ctx context.Context, review domain.Review) (domain.PullRequest, error) {
+	if !domain.ValidVerdict(review.Verdict) {
+		return domain.PullRequest{}, ErrInvalidVerdict
+	}
 
-	err = s.PrRepository.ReplaceReviewer(ctx, prID, oldRevId, newRevID)
+	pr, err := s.PrRepository.GetPRWithReviewers(ctx, review.PullRequestID)
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PullRequest{}, ErrPrNotFound
+		}
+		return domain.PullRequest{}, err
+	}
+
+	if pr.Status != domain.PrStatusOpen {
+		return domain.PullRequest{}, statusError(pr.Status)
+	}
+
+	if !slices.Contains(pr.ReviewersIDs, review.ReviewerID) {
+		return domain.PullRequest{}, ErrUserIsNotReviewer
+	}
+
+	review.SubmittedAt = time.Now()
+	_, err = s.PrRepository.SubmitReview(ctx, review)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return s.PrRepository.GetPRWithReviewers(ctx, review.PullRequestID)
+}
+
+func (s *PrService) Reassign(
+	ctx context.Context,
+	prID, oldRevId string,
+) (domain.PullRequest, AssignedReviewer, error) {
+	pr, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PullRequest{}, AssignedReviewer{}, ErrPrNotFound
+		}
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+
+	if err := canReassign(pr, oldRevId); err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+
+	author, err := s.URepository.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+	settings, err := s.TRepository.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+
+	owners, err := s.ownerTier(ctx, author.TeamName, pr.ChangedFiles)
+	if err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+
+	selected, pool, err := s.pickReviewers(ctx, reviewerQuery{
+		HomeTeam:       author.TeamName,
+		FallbackTeams:  settings.FallbackTeams,
+		Exclude:        append([]string{oldRevId, pr.AuthorID}, pr.ReviewersIDs...),
+		Preferred:      []preferenceTier{owners, labelTier(pr.Labels)},
+		Count:          1,
+		Required:       1,
+		CapacityPolicy: settings.CapacityPolicy,
+	})
+	if err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+	if len(selected) == 0 {
+		return domain.PullRequest{}, AssignedReviewer{}, ErrNoCandidate
+	}
+	newRev := selected[0]
+
+	// The pull request may have been merged or the reviewer replaced since
+	// it was read, so the checks are repeated under the lock.
+	err = s.PrRepository.ReplaceReviewer(
+		ctx,
+		prID,
+		oldRevId,
+		newRev.record(pool, oldRevId, time.Now()),
+		func(_ context.Context, current domain.PullRequest) error {
+			return canReassign(current, oldRevId)
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PullRequest{}, AssignedReviewer{}, ErrPrNotFound
+	}
+	if err != nil {
+		return domain.PullRequest{}, AssignedReviewer{}, err
 	}
 
 	pullRequest, err := s.PrRepository.GetPRWithReviewers(ctx, prID)
 	if err != nil {
-		return domain.PullRequest{}, "", err
+		return domain.PullRequest{}, AssignedReviewer{}, err
+	}
+
+	return pullRequest, newRev, nil
+}
+
+// canReassign reports why the reviewer of pr cannot be replaced, if so.
+func canReassign(pr domain.PullRequest, reviewerID string) error {
+	if pr.Status != domain.PrStatusOpen {
+		return statusError(pr.Status)
+	}
+	if !slices.Contains(pr.ReviewersIDs, reviewerID) {
+		return ErrUserIsNotReviewer
+	}
+	return nil
+}
+
+// Get returns the pull request with its author and reviewers, in the
+// order they were assigned, each with their latest verdict.
+func (s *PrService) Get(ctx context.Context, prID string) (PrDetails, error) {
+	snapshot, err := s.PrRepository.GetPRSnapshot(ctx, prID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PrDetails{}, ErrPrNotFound
+		}
+		return PrDetails{}, err
+	}
+	pr, assignments, users := snapshot.PullRequest, snapshot.Assignments, snapshot.Users
+
+	author, ok := users[pr.AuthorID]
+	if !ok {
+		return PrDetails{}, fmt.Errorf("author %s of pull_request %s: %w", pr.AuthorID, prID, sql.ErrNoRows)
+	}
+
+	reviews := make(map[string]domain.Review, len(pr.Reviews))
+	for _, rv := range pr.Reviews {
+		reviews[rv.ReviewerID] = rv
+	}
+
+	reviewers := make([]ReviewerDetails, 0, len(assignments))
+	for _, a := range assignments {
+		user, ok := users[a.UserID]
+		if !ok {
+			return PrDetails{}, fmt.Errorf("reviewer %s of pull_request %s: %w", a.UserID, prID, sql.ErrNoRows)
+		}
+		details := ReviewerDetails{User: user, AssignedAt: a.AssignedAt}
+		if rv, ok := reviews[a.UserID]; ok {
+			details.Review = &rv
+		}
+		reviewers = append(reviewers, details)
+	}
+
+	return PrDetails{PullRequest: pr, Author: author, Reviewers: reviewers}, nil
+}
+
+// List returns one page of pull requests and the cursor for the next page,
+// which is nil when there are no more.
+func (s *PrService) List(ctx context.Context, filter domain.PrFilter) ([]domain.PullRequest, *domain.PrCursor, error) {
+	if filter.Limit < 0 || filter.Limit > maxPrPageSize {
+		return nil, nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPrFilter, maxPrPageSize)
+	}
+	for _, st := range filter.Statuses {
+		switch st {
+		case domain.PrStatusDraft, domain.PrStatusOpen, domain.PrStatusMerged, domain.PrStatusClosed:
+		default:
+			return nil, nil, fmt.Errorf("%w: status %q is not one of DRAFT, OPEN, MERGED, CLOSED", ErrInvalidPrFilter, st)
+		}
+	}
+	for _, r := range []struct {
+		name     string
+		from, to *time.Time
+	}{
+		{"created", filter.CreatedFrom, filter.CreatedTo},
+		{"merged", filter.MergedFrom, filter.MergedTo},
+	} {
+		if r.from != nil && r.to != nil && !r.from.Before(*r.to) {
+			return nil, nil, fmt.Errorf("%w: %s_from must be before %s_to", ErrInvalidPrFilter, r.name, r.name)
+		}
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPrPageSize
+	}
+
+	prs, err := s.PrRepository.ListPRs(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *domain.PrCursor
+	if len(prs) == filter.Limit {
+		last := prs[len(prs)-1]
+		next = &domain.PrCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return prs, next, nil
+}
+
+func (s *PrService) History(ctx context.Context, prID string) ([]domain.PrTransition, error) {
+	ok, err := s.PrRepository.PRExists(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPrNotFound
+	}
+
+	return s.PrRepository.GetStatusHistory(ctx, prID)
+}
+
+func (s *PrService) Escalations(ctx context.Context, prID string) ([]domain.Escalation, error) {
+	ok, err := s.PrRepository.PRExists(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPrNotFound
+	}
+
+	return s.PrRepository.GetEscalations(ctx, prID)
+}
+
+func (s *PrService) ExplainAssignment(ctx context.Context, prID string) ([]domain.ReviewerAssignment, error) {
+	ok, err := s.PrRepository.PRExists(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPrNotFound
 	}
 
-	return pullRequest, newRevID, nil
+	return s.PrRepository.GetReviewerAssignments(ctx, prID)
 }
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListRejectsInvalidFilter(t *testing.T) {
+	at := func(day int) *time.Time {
+		ts := time.Date(2025, 10, day, 0, 0, 0, 0, time.UTC)
+		return &ts
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.PrFilter
+		want   string
+	}{
+		{"limit above maximum", domain.PrFilter{Limit: 501},
+			"invalid pull_request filter: limit must be between 1 and 500"},
+		{"unknown status", domain.PrFilter{Statuses: []domain.PrStatus{domain.PrStatusOpen, "DONE"}},
+			`invalid pull_request filter: status "DONE" is not one of DRAFT, OPEN, MERGED, CLOSED`},
+		{"created range reversed", domain.PrFilter{CreatedFrom: at(2), CreatedTo: at(1)},
+			"invalid pull_request filter: created_from must be before created_to"},
+		{"empty merged range", domain.PrFilter{MergedFrom: at(1), MergedTo: at(1)},
+			"invalid pull_request filter: merged_from must be before merged_to"},
+	}
+
+	s := &PrService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.List(context.Background(), tt.filter)
+			if !errors.Is(err, ErrInvalidPrFilter) {
+				t.Fatalf("List() error = %v, want ErrInvalidPrFilter", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("List() error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+var ErrUnknownStrategy = errors.New("unknown reviewer selection strategy")
+
+type SelectionRequest struct {
+	TeamName   string
+	Candidates []domain.User
+	Count      int
+	// Rand, when set, replaces the global source so that selection is reproducible.
+	Rand *rand.Rand
+	// DryRun asks stateful selectors not to remember this selection.
+	DryRun bool
+}
+
+func (r SelectionRequest) perm(n int) []int {
+	if r.Rand != nil {
+		return r.Rand.Perm(n)
+	}
+	return rand.Perm(n)
+}
+
+type ReviewerSelector interface {
+	Select(ctx context.Context, req SelectionRequest) ([]domain.User, error)
+	// Strategy names the strategy used for the team, for assignment rationale.
+	Strategy(teamName string) string
+}
+
+type ReviewLoadSource interface {
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+func NewReviewerSelector(strategy string, loads ReviewLoadSource) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return RandomSelector{}, nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyLeastLoaded:
+		return &LeastLoadedSelector{Loads: loads}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, strategy)
+	}
+}
+
+type TeamSelector struct {
+	Default ReviewerSelector
+	Teams   map[string]ReviewerSelector
+}
+
+func NewTeamSelector(
+	defaultStrategy string,
+	teamStrategies map[string]string,
+	loads ReviewLoadSource,
+) (*TeamSelector, error) {
+	def, err := NewReviewerSelector(defaultStrategy, loads)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make(map[string]ReviewerSelector, len(teamStrategies))
+	for team, strategy := range teamStrategies {
+		sel, err := NewReviewerSelector(strategy, loads)
+		if err != nil {
+			return nil, fmt.Errorf("team %q: %w", team, err)
+		}
+		teams[team] = sel
+	}
+
+	return &TeamSelector{Default: def, Teams: teams}, nil
+}
+
+func (s *TeamSelector) Select(ctx context.Context, req SelectionRequest) ([]domain.User, error) {
+	return s.forTeam(req.TeamName).Select(ctx, req)
+}
+
+func (s *TeamSelector) Strategy(teamName string) string {
+	return s.forTeam(teamName).Strategy(teamName)
+}
+
+func (s *TeamSelector) forTeam(teamName string) ReviewerSelector {
+	if sel, ok := s.Teams[teamName]; ok {
+		return sel
+	}
+	return s.Default
+}
+
+type RandomSelector struct{}
+
+func (RandomSelector) Strategy(string) string {
+	return StrategyRandom
+}
+
+func (RandomSelector) Select(_ context.Context, req SelectionRequest) ([]domain.User, error) {
+	count := min(req.Count, len(req.Candidates))
+	selected := make([]domain.User, 0, count)
+	for _, i := range req.perm(len(req.Candidates))[:count] {
+		selected = append(selected, req.Candidates[i])
+	}
+	return selected, nil
+}
+
+// RoundRobinSelector walks team members in user_id order, continuing after
+// the last reviewer it picked for the team. State is kept in memory.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[string]string)}
+}
+
+func (s *RoundRobinSelector) Strategy(string) string {
+	return StrategyRoundRobin
+}
+
+func (s *RoundRobinSelector) Select(_ context.Context, req SelectionRequest) ([]domain.User, error) {
+	count := min(req.Count, len(req.Candidates))
+	if count == 0 {
+		return nil, nil
+	}
+
+	ordered := sortedByID(req.Candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].ID > s.last[req.TeamName]
+	})
+
+	selected := make([]domain.User, 0, count)
+	for i := 0; i < count; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	if !req.DryRun {
+		s.last[req.TeamName] = selected[len(selected)-1].ID
+	}
+
+	return selected, nil
+}
+
+type LeastLoadedSelector struct {
+	Loads ReviewLoadSource
+}
+
+func (s *LeastLoadedSelector) Strategy(string) string {
+	return StrategyLeastLoaded
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, req SelectionRequest) ([]domain.User, error) {
+	count := min(req.Count, len(req.Candidates))
+	if count == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(req.Candidates))
+	for _, c := range req.Candidates {
+		ids = append(ids, c.ID)
+	}
+	loads, err := s.Loads.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shuffle first so that the stable sort breaks ties randomly.
+	ordered := make([]domain.User, len(req.Candidates))
+	for i, j := range req.perm(len(req.Candidates)) {
+		ordered[i] = req.Candidates[j]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return loads[ordered[i].ID] < loads[ordered[j].ID]
+	})
+
+	return ordered[:count], nil
+}
+
+func sortedByID(users []domain.User) []domain.User {
+	ordered := make([]domain.User, len(users))
+	copy(ordered, users)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
+	return ordered
+}
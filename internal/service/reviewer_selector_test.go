@@ -0,0 +1,276 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func users(ids ...string) []domain.User {
+	out := make([]domain.User, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, domain.User{ID: id, Username: id, IsActive: true})
+	}
+	return out
+}
+
+func userIDs(us []domain.User) []string {
+	ids := make([]string, 0, len(us))
+	for _, u := range us {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestRandomSelector(t *testing.T) {
+	candidates := users("u1", "u2", "u3", "u4")
+
+	tests := []struct {
+		name  string
+		count int
+		want  int
+	}{
+		{"fewer than candidates", 2, 2},
+		{"all candidates", 4, 4},
+		{"more than candidates", 6, 4},
+		{"none", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RandomSelector{}.Select(context.Background(), SelectionRequest{
+				TeamName:   "backend",
+				Candidates: candidates,
+				Count:      tt.count,
+			})
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			ids := userIDs(got)
+			if len(ids) != tt.want {
+				t.Fatalf("Select() = %v, want %d reviewers", ids, tt.want)
+			}
+			slices.Sort(ids)
+			if len(slices.Compact(ids)) != tt.want {
+				t.Errorf("Select() = %v, want distinct reviewers", userIDs(got))
+			}
+			for _, id := range ids {
+				if !slices.Contains(userIDs(candidates), id) {
+					t.Errorf("Select() picked %q, not a candidate", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomSelectorIsReproducibleWithSeed(t *testing.T) {
+	candidates := users("u1", "u2", "u3", "u4", "u5")
+	pick := func() []string {
+		got, err := RandomSelector{}.Select(context.Background(), SelectionRequest{
+			Candidates: candidates,
+			Count:      3,
+			Rand:       rand.New(rand.NewSource(42)),
+		})
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		return userIDs(got)
+	}
+
+	first, second := pick(), pick()
+	if !slices.Equal(first, second) {
+		t.Errorf("same seed picked %v and %v", first, second)
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	s := NewRoundRobinSelector()
+	// Candidates arrive in any order, the rotation follows user_id.
+	backend := users("u3", "u1", "u2")
+	mobile := users("m2", "m1")
+
+	steps := []struct {
+		team       string
+		candidates []domain.User
+		count      int
+		dryRun     bool
+		want       []string
+	}{
+		{"backend", backend, 1, false, []string{"u1"}},
+		{"backend", backend, 1, false, []string{"u2"}},
+		{"mobile", mobile, 1, false, []string{"m1"}},
+		{"backend", backend, 2, false, []string{"u3", "u1"}},
+		{"backend", backend, 1, true, []string{"u2"}},
+		{"backend", backend, 1, false, []string{"u2"}},
+		{"mobile", mobile, 2, false, []string{"m2", "m1"}},
+		{"backend", users("u1", "u4"), 1, false, []string{"u4"}},
+		{"backend", backend, 5, false, []string{"u1", "u2", "u3"}},
+	}
+
+	for i, step := range steps {
+		got, err := s.Select(context.Background(), SelectionRequest{
+			TeamName:   step.team,
+			Candidates: step.candidates,
+			Count:      step.count,
+			DryRun:     step.dryRun,
+		})
+		if err != nil {
+			t.Fatalf("step %d: Select: %v", i, err)
+		}
+		if ids := userIDs(got); !slices.Equal(ids, step.want) {
+			t.Errorf("step %d (%s): Select() = %v, want %v", i, step.team, ids, step.want)
+		}
+	}
+}
+
+func TestNewTeamSelector(t *testing.T) {
+	s, err := NewTeamSelector(StrategyRandom, map[string]string{
+		"backend": StrategyRoundRobin,
+		"mobile":  StrategyLeastLoaded,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewTeamSelector: %v", err)
+	}
+
+	for team, want := range map[string]string{
+		"backend":  StrategyRoundRobin,
+		"mobile":   StrategyLeastLoaded,
+		"frontend": StrategyRandom,
+	} {
+		if got := s.Strategy(team); got != want {
+			t.Errorf("Strategy(%q) = %q, want %q", team, got, want)
+		}
+	}
+
+	// The override keeps its own rotation state for the team.
+	candidates := users("u2", "u1")
+	for _, want := range []string{"u1", "u2", "u1"} {
+		got, err := s.Select(context.Background(), SelectionRequest{TeamName: "backend", Candidates: candidates, Count: 1})
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if ids := userIDs(got); len(ids) != 1 || ids[0] != want {
+			t.Errorf("Select() = %v, want [%s]", ids, want)
+		}
+	}
+}
+
+func TestNewTeamSelectorRejectsUnknownStrategy(t *testing.T) {
+	if _, err := NewTeamSelector("fastest", nil, nil); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown default strategy: error = %v, want ErrUnknownStrategy", err)
+	}
+	if _, err := NewTeamSelector(StrategyRandom, map[string]string{"backend": "fastest"}, nil); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("unknown team strategy: error = %v, want ErrUnknownStrategy", err)
+	}
+}
+
+// fakeReviewLoads counts open reviews the way the repository does: only
+// reviews on OPEN pull requests add to a reviewer's load.
+type fakeReviewLoads struct {
+	prs   []domain.PullRequest
+	err   error
+	calls [][]string
+}
+
+func (f *fakeReviewLoads) CountOpenReviews(_ context.Context, userIDs []string) (map[string]int, error) {
+	f.calls = append(f.calls, slices.Clone(userIDs))
+	if f.err != nil {
+		return nil, f.err
+	}
+	loads := make(map[string]int)
+	for _, pr := range f.prs {
+		if pr.Status != domain.PrStatusOpen {
+			continue
+		}
+		for _, id := range pr.ReviewersIDs {
+			if slices.Contains(userIDs, id) {
+				loads[id]++
+			}
+		}
+	}
+	return loads, nil
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	pr := func(status domain.PrStatus, reviewers ...string) domain.PullRequest {
+		return domain.PullRequest{Status: status, ReviewersIDs: reviewers}
+	}
+	loads := &fakeReviewLoads{prs: []domain.PullRequest{
+		pr(domain.PrStatusOpen, "u1", "u2"),
+		pr(domain.PrStatusOpen, "u1", "u3"),
+		pr(domain.PrStatusOpen, "u1"),
+		pr(domain.PrStatusMerged, "u4", "u5"),
+		pr(domain.PrStatusMerged, "u4"),
+		pr(domain.PrStatusClosed, "u4", "u5"),
+	}}
+	s := &LeastLoadedSelector{Loads: loads}
+
+	tests := []struct {
+		name       string
+		candidates []domain.User
+		count      int
+		want       [][]string
+	}{
+		{"least loaded first", users("u1", "u2"), 1, [][]string{{"u2"}}},
+		{"merged and closed reviews do not count", users("u1", "u2", "u4"), 1, [][]string{{"u4"}}},
+		{"ordered by load", users("u1", "u2", "u4"), 3, [][]string{{"u4", "u2", "u1"}}},
+		{"tie broken either way", users("u1", "u4", "u5"), 1, [][]string{{"u4"}, {"u5"}}},
+		{"ties come before higher loads", users("u1", "u2", "u3"), 2, [][]string{{"u2", "u3"}, {"u3", "u2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[int]bool)
+			for seed := int64(0); seed < 50; seed++ {
+				got, err := s.Select(context.Background(), SelectionRequest{
+					Candidates: tt.candidates,
+					Count:      tt.count,
+					Rand:       rand.New(rand.NewSource(seed)),
+				})
+				if err != nil {
+					t.Fatalf("Select: %v", err)
+				}
+				ids := userIDs(got)
+				i := slices.IndexFunc(tt.want, func(w []string) bool { return slices.Equal(w, ids) })
+				if i < 0 {
+					t.Fatalf("seed %d: Select() = %v, want one of %v", seed, ids, tt.want)
+				}
+				seen[i] = true
+			}
+			if len(seen) != len(tt.want) {
+				t.Errorf("tie-break never produced some of %v", tt.want)
+			}
+		})
+	}
+}
+
+func TestLeastLoadedSelectorCountsCandidatesOnce(t *testing.T) {
+	loads := &fakeReviewLoads{}
+	s := &LeastLoadedSelector{Loads: loads}
+
+	got, err := s.Select(context.Background(), SelectionRequest{Candidates: users("u1", "u2"), Count: 1})
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("Select() = %v, want one reviewer", userIDs(got))
+	}
+	if len(loads.calls) != 1 || !slices.Equal(loads.calls[0], []string{"u1", "u2"}) {
+		t.Errorf("CountOpenReviews calls = %v, want one call for [u1 u2]", loads.calls)
+	}
+
+	if _, err := s.Select(context.Background(), SelectionRequest{Candidates: users("u1"), Count: 0}); err != nil {
+		t.Fatalf("Select with zero count: %v", err)
+	}
+	if len(loads.calls) != 1 {
+		t.Errorf("Select with zero count queried loads")
+	}
+
+	loads.err = errors.New("db is down")
+	if _, err := s.Select(context.Background(), SelectionRequest{Candidates: users("u1"), Count: 1}); !errors.Is(err, loads.err) {
+		t.Errorf("Select() error = %v, want %v", err, loads.err)
+	}
+}
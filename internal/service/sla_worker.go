@@ -0,0 +1,108 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// SLAActor is recorded as the actor of changes made by the SLA worker.
+const SLAActor = "sla-worker"
+
+const defaultSLABatchSize = 100
+
+type SLARepository interface {
+	WithScanLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+	FindOverdueReviews(ctx context.Context, now time.Time, limit int) ([]domain.OverdueReview, error)
+	RecordEscalation(ctx context.Context, e domain.Escalation) (bool, error)
+}
+
+// SLAWorker periodically looks for reviewers who missed their team's
+// review SLA and escalates or reassigns them. Scans are serialized
+// through a database lock, so every replica may run a worker.
+type SLAWorker struct {
+	Repository SLARepository
+	PrService  *PrService
+	Interval   time.Duration
+	BatchSize  int
+}
+
+func (w *SLAWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := w.Scan(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("sla: scan failed: %v", err)
+			}
+		}
+	}
+}
+
+// Scan handles up to BatchSize overdue reviews, most overdue first;
+// the rest are picked up by the following scans.
+func (w *SLAWorker) Scan(ctx context.Context) error {
+	batch := w.BatchSize
+	if batch <= 0 {
+		batch = defaultSLABatchSize
+	}
+
+	_, err := w.Repository.WithScanLock(ctx, func(ctx context.Context) error {
+		ctx = WithActor(ctx, SLAActor)
+		overdue, err := w.Repository.FindOverdueReviews(ctx, time.Now(), batch)
+		if err != nil {
+			return err
+		}
+		for _, o := range overdue {
+			err = w.handle(ctx, o)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				// One broken review must not hold up the rest of the batch;
+				// it is retried on the next scan.
+				log.Printf("sla: handling %s/%s failed: %v", o.PullRequestID, o.ReviewerID, err)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (w *SLAWorker) handle(ctx context.Context, o domain.OverdueReview) error {
+	escalation := domain.Escalation{
+		PullRequestID: o.PullRequestID,
+		ReviewerID:    o.ReviewerID,
+		Action:        domain.EscalationEscalated,
+		SLAStartedAt:  o.StartedAt,
+		CreatedAt:     time.Now(),
+	}
+
+	if o.Action == domain.SLAActionReassign {
+		_, newRev, err := w.PrService.Reassign(ctx, o.PullRequestID, o.ReviewerID)
+		switch {
+		case err == nil:
+			escalation.Action = domain.EscalationReassigned
+			escalation.ReplacedBy = newRev.User.ID
+		case errors.Is(err, ErrNoCandidate), errors.Is(err, ErrReviewersAtCapacity):
+			// Nobody can take over; escalate so that the miss is still visible.
+			escalation.Note = "reassign failed: " + err.Error()
+		case errors.Is(err, ErrPrNotFound), errors.Is(err, ErrUserIsNotReviewer),
+			errors.Is(err, ErrPrAlreadyMerged), errors.Is(err, ErrPrClosed), errors.Is(err, ErrPrDraft):
+			// The pull request changed since the scan started.
+			return nil
+		default:
+			return err
+		}
+	}
+
+	_, err := w.Repository.RecordEscalation(ctx, escalation)
+	return err
+}
@@ -3,14 +3,42 @@ package service
 import (
 	"PR_project/internal/domain"
 	"context"
+	"database/sql"
 	"errors"
+	"slices"
+	"strings"
 )
 
 var (
 	ErrTeamAlreadyExists = errors.New("team already exists")
 	ErrTeamNotFound      = errors.New("team not found")
+	ErrInvalidSettings   = errors.New("invalid team settings")
+	ErrFallbackNotFound  = errors.New("fallback team not found")
+	ErrInvalidMember     = errors.New("invalid team member")
+	ErrMemberExists      = errors.New("user is already a member of the team")
+	ErrUserInOtherTeam   = errors.New("user belongs to another team")
+	ErrMemberNotFound    = errors.New("user is not a member of the team")
+	ErrInvalidOnConflict = errors.New("invalid on_conflict mode")
 )
 
+// MemberConflictError lists requested members who already belong to
+// another team. It matches ErrUserInOtherTeam.
+type MemberConflictError struct {
+	Conflicts []domain.User
+}
+
+func (e *MemberConflictError) Error() string {
+	ids := make([]string, 0, len(e.Conflicts))
+	for _, u := range e.Conflicts {
+		ids = append(ids, u.ID+" ("+u.TeamName+")")
+	}
+	return ErrUserInOtherTeam.Error() + ": " + strings.Join(ids, ", ")
+}
+
+func (e *MemberConflictError) Unwrap() error {
+	return ErrUserInOtherTeam
+}
+
 type TeamRepository interface {
 	TeamExists(ctx context.Context, teamName string) (bool, error)
 	GetTeam(ctx context.Context, teamName string) (domain.Team, []domain.User, error)
@@ -18,17 +46,54 @@ type TeamRepository interface {
 		ctx context.Context,
 		teamName string,
 		members []TeamMemberInput,
+		resolve func(ctx context.Context, existing []domain.User) ([]TeamMemberInput, error),
 	) (domain.Team, []domain.User, error)
+	GetSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	UpsertSettings(
+		ctx context.Context,
+		teamName string,
+		apply func(ctx context.Context, current domain.TeamSettings) (domain.TeamSettings, error),
+	) (domain.TeamSettings, error)
+	GetOwnershipRules(ctx context.Context, teamName string) ([]domain.OwnershipRule, error)
+	ReplaceOwnershipRules(ctx context.Context, teamName string, rules []domain.OwnershipRule) error
+	AddTeamMember(ctx context.Context, teamName string, member TeamMemberInput) (domain.User, error)
+	RenameTeamMember(ctx context.Context, teamName, userID, username string) (domain.User, error)
 }
 
 type TeamService struct {
 	TRepository TeamRepository
+	URepository UserRepository
+	// UserService deactivates members, handing their open reviews over.
+	UserService *UserService
+}
+
+// MemberUpdate changes a team member; nil fields are left as they are.
+type MemberUpdate struct {
+	Username *string
+	IsActive *bool
 }
 
 type TeamMemberInput struct {
 	UserID   string
 	Username string
 	IsActive bool
+	// Tags are left untouched for existing users when nil.
+	Tags []string
+}
+
+// TeamCreation is the outcome of creating a team. Moved and Skipped are
+// the requested members who belonged to another team, as they were before.
+type TeamCreation struct {
+	Team    domain.Team
+	Members []domain.User
+	Moved   []domain.User
+	Skipped []domain.User
+}
+
+// TeamSnapshot is how a created team is recorded in the audit log.
+type TeamSnapshot struct {
+	Team    domain.Team
+	Members []domain.User
 }
 
 func (s *TeamService) GetTeam(ctx context.Context, teamName string) (domain.Team, []domain.User, error) {
@@ -43,22 +108,360 @@ func (s *TeamService) GetTeam(ctx context.Context, teamName string) (domain.Team
 	return s.TRepository.GetTeam(ctx, teamName)
 }
 
+// CreateTeam creates a team with its members. Requested members who
+// already belong to another team are handled according to onConflict:
+// fail rejects the team with a MemberConflictError, move takes them over
+// and skip leaves them where they are. Users without a team are always
+// taken in.
 func (s *TeamService) CreateTeam(
 	ctx context.Context,
 	teamName string,
-	teamMembers []TeamMemberInput) (domain.Team, []domain.User, error) {
+	teamMembers []TeamMemberInput,
+	onConflict string,
+) (TeamCreation, error) {
+	if onConflict == "" {
+		onConflict = domain.OnConflictFail
+	}
+	if !domain.ValidOnConflict(onConflict) {
+		return TeamCreation{}, ErrInvalidOnConflict
+	}
+
 	ok, err := s.TRepository.TeamExists(ctx, teamName)
 	if err != nil {
-		return domain.Team{}, nil, err
+		return TeamCreation{}, err
 	}
 	if ok {
-		return domain.Team{}, nil, ErrTeamAlreadyExists
+		return TeamCreation{}, ErrTeamAlreadyExists
+	}
+
+	for i := range teamMembers {
+		if teamMembers[i].Tags != nil {
+			teamMembers[i].Tags = domain.NormalizeTags(teamMembers[i].Tags)
+		}
+	}
+
+	var result TeamCreation
+	team, users, err := s.TRepository.CreateTeamWithMembers(
+		ctx,
+		teamName,
+		teamMembers,
+		func(ctx context.Context, existing []domain.User) ([]TeamMemberInput, error) {
+			var conflicts []domain.User
+			for _, u := range existing {
+				if u.TeamName != "" && u.TeamName != teamName {
+					conflicts = append(conflicts, u)
+				}
+			}
+			if len(conflicts) == 0 {
+				return teamMembers, nil
+			}
+
+			switch onConflict {
+			case domain.OnConflictMove:
+				result.Moved = conflicts
+				return teamMembers, nil
+			case domain.OnConflictSkip:
+				result.Skipped = conflicts
+				return slices.DeleteFunc(slices.Clone(teamMembers), func(m TeamMemberInput) bool {
+					return slices.ContainsFunc(conflicts, func(u domain.User) bool { return u.ID == m.UserID })
+				}), nil
+			}
+			return nil, &MemberConflictError{Conflicts: conflicts}
+		},
+	)
+	if err != nil {
+		return TeamCreation{}, err
+	}
+	result.Team = team
+	result.Members = users
+
+	return result, nil
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	ok, err := s.TRepository.TeamExists(ctx, teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if !ok {
+		return domain.TeamSettings{}, ErrTeamNotFound
+	}
+
+	return s.TRepository.GetSettings(ctx, teamName)
+}
+
+// SettingsUpdate is a partial change of a team's settings. Nil fields keep
+// their current value; a non-nil empty FallbackTeams clears the list.
+type SettingsUpdate struct {
+	TeamName          string
+	MinReviewers      *int
+	MaxReviewers      *int
+	FallbackTeams     *[]string
+	MaxOpenReviews    NullableInt
+	CapacityPolicy    *string
+	ApprovalPolicy    *string
+	RequiredApprovals *int
+	ReviewSLAMinutes  NullableInt
+	SLAAction         *string
+}
+
+// NullableInt changes a nullable setting: with Set false the setting is
+// kept, otherwise it becomes Value, and a nil Value clears it.
+type NullableInt struct {
+	Set   bool
+	Value *int
+}
+
+// Apply returns current with the fields present in u replaced.
+func (u SettingsUpdate) Apply(current domain.TeamSettings) domain.TeamSettings {
+	next := current
+	next.FallbackTeams = slices.Clone(current.FallbackTeams)
+	if u.MinReviewers != nil {
+		next.MinReviewers = *u.MinReviewers
+	}
+	if u.MaxReviewers != nil {
+		next.MaxReviewers = *u.MaxReviewers
+	}
+	if u.FallbackTeams != nil {
+		next.FallbackTeams = slices.Clone(*u.FallbackTeams)
+	}
+	if u.MaxOpenReviews.Set {
+		next.MaxOpenReviews = u.MaxOpenReviews.Value
+	}
+	if u.CapacityPolicy != nil {
+		next.CapacityPolicy = *u.CapacityPolicy
+	}
+	if u.ApprovalPolicy != nil {
+		next.ApprovalPolicy = *u.ApprovalPolicy
+	}
+	if u.RequiredApprovals != nil {
+		next.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.ReviewSLAMinutes.Set {
+		next.ReviewSLAMinutes = u.ReviewSLAMinutes.Value
+	}
+	if u.SLAAction != nil {
+		next.SLAAction = *u.SLAAction
+	}
+	return next
+}
+
+// UpdateSettings merges the update over the team's current settings under
+// the team's lock, so concurrent partial updates of different fields do not
+// overwrite each other. The merged settings are validated as a whole.
+func (s *TeamService) UpdateSettings(ctx context.Context, update SettingsUpdate) (domain.TeamSettings, error) {
+	updated, err := s.TRepository.UpsertSettings(
+		ctx,
+		update.TeamName,
+		func(ctx context.Context, current domain.TeamSettings) (domain.TeamSettings, error) {
+			next := update.Apply(current)
+			return next, s.validateSettings(ctx, next)
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.TeamSettings{}, ErrTeamNotFound
+	}
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return updated, nil
+}
+
+func (s *TeamService) validateSettings(ctx context.Context, settings domain.TeamSettings) error {
+	if settings.MinReviewers < 0 || settings.MaxReviewers < settings.MinReviewers {
+		return ErrInvalidSettings
+	}
+	if settings.MaxOpenReviews != nil && *settings.MaxOpenReviews <= 0 {
+		return ErrInvalidSettings
+	}
+	if !domain.ValidCapacityPolicy(settings.CapacityPolicy) {
+		return ErrInvalidSettings
+	}
+	if !domain.ValidApprovalPolicy(settings.ApprovalPolicy) || settings.RequiredApprovals < 1 {
+		return ErrInvalidSettings
+	}
+	if !domain.ValidSLAAction(settings.SLAAction) ||
+		(settings.ReviewSLAMinutes != nil && *settings.ReviewSLAMinutes <= 0) {
+		return ErrInvalidSettings
+	}
+	for i, fallback := range settings.FallbackTeams {
+		if fallback == "" || fallback == settings.TeamName ||
+			slices.Contains(settings.FallbackTeams[:i], fallback) {
+			return ErrInvalidSettings
+		}
+	}
+
+	for _, fallback := range settings.FallbackTeams {
+		ok, err := s.TRepository.TeamExists(ctx, fallback)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrFallbackNotFound
+		}
 	}
 
-	team, users, err := s.TRepository.CreateTeamWithMembers(ctx, teamName, teamMembers)
+	return nil
+}
+
+func (s *TeamService) GetOwnershipRules(ctx context.Context, teamName string) ([]domain.OwnershipRule, error) {
+	ok, err := s.TRepository.TeamExists(ctx, teamName)
 	if err != nil {
-		return team, nil, err
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTeamNotFound
+	}
+
+	return s.TRepository.GetOwnershipRules(ctx, teamName)
+}
+
+func (s *TeamService) SetCodeowners(ctx context.Context, teamName, content string) ([]domain.OwnershipRule, error) {
+	rules, err := domain.ParseCodeowners(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.TRepository.ReplaceOwnershipRules(ctx, teamName, rules)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// AddMember adds a new hire to an existing team. A user who was removed
+// from their previous team may be added again; a user who belongs to a
+// team is left alone.
+func (s *TeamService) AddMember(ctx context.Context, teamName string, member TeamMemberInput) (domain.User, error) {
+	if member.UserID == "" {
+		return domain.User{}, ErrInvalidMember
+	}
+	if member.Tags != nil {
+		member.Tags = domain.NormalizeTags(member.Tags)
+	}
+
+	ok, err := s.TRepository.TeamExists(ctx, teamName)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !ok {
+		return domain.User{}, ErrTeamNotFound
+	}
+
+	user, err := s.TRepository.AddTeamMember(ctx, teamName, member)
+	if errors.Is(err, sql.ErrNoRows) {
+		current, err := s.URepository.GetUserByID(ctx, member.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, ErrUserNotFound
+		}
+		if err != nil {
+			return domain.User{}, err
+		}
+		if current.TeamName == teamName {
+			return domain.User{}, ErrMemberExists
+		}
+		return domain.User{}, ErrUserInOtherTeam
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdateMember renames a member and/or changes their active flag.
+// Deactivating hands the member's open reviews over, see UserService.Deactivate.
+// The active flag is changed first, so that a failed deactivation leaves
+// the member as they were; the rename comes last.
+func (s *TeamService) UpdateMember(
+	ctx context.Context,
+	teamName, userID string,
+	update MemberUpdate,
+) (domain.User, []domain.ReviewHandover, error) {
+	if userID == "" || (update.Username == nil && update.IsActive == nil) {
+		return domain.User{}, nil, ErrInvalidMember
+	}
+
+	before, err := s.getMember(ctx, teamName, userID)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	user := before
+	var handovers []domain.ReviewHandover
+	if update.IsActive != nil && *update.IsActive != before.IsActive {
+		user, handovers, err = s.UserService.SetIsActive(ctx, userID, *update.IsActive)
+		if err != nil {
+			return domain.User{}, nil, err
+		}
+	}
+
+	if update.Username != nil && *update.Username != before.Username {
+		user, err = s.TRepository.RenameTeamMember(ctx, teamName, userID, *update.Username)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, nil, ErrMemberNotFound
+		}
+		if err != nil {
+			return domain.User{}, nil, err
+		}
+	}
+
+	return user, handovers, nil
+}
+
+// RemoveMember takes a leaver out of the team in one transaction: their
+// open reviews are handed over to teammates and they are deactivated.
+// Their history stays, and pull requests they authored remain open and
+// are listed in the report so that someone can pick them up.
+func (s *TeamService) RemoveMember(ctx context.Context, teamName, userID string) (DeactivationReport, error) {
+	if userID == "" {
+		return DeactivationReport{}, ErrInvalidMember
+	}
+
+	_, err := s.getMember(ctx, teamName, userID)
+	if err != nil {
+		return DeactivationReport{}, err
+	}
+
+	report, err := s.UserService.Deactivate(ctx, domain.DeactivationTarget{
+		TeamName:  teamName,
+		UserIDs:   []string{userID},
+		LeaveTeam: true,
+	})
+	if errors.Is(err, ErrUserNotFound) {
+		return DeactivationReport{}, ErrMemberNotFound
+	}
+	if err != nil {
+		return DeactivationReport{}, err
+	}
+
+	return report, nil
+}
+
+func (s *TeamService) getMember(ctx context.Context, teamName, userID string) (domain.User, error) {
+	ok, err := s.TRepository.TeamExists(ctx, teamName)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !ok {
+		return domain.User{}, ErrTeamNotFound
+	}
+
+	user, err := s.URepository.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.TeamName != teamName {
+		return domain.User{}, ErrMemberNotFound
 	}
 
-	return team, users, nil
+	return user, nil
 }
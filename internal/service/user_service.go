@@ -3,38 +3,158 @@ package service
 import (
 	"PR_project/internal/domain"
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrInvalidAbsence  = errors.New("invalid absence period")
+	ErrAbsenceNotFound = errors.New("absence not found")
+	ErrInvalidCapacity = errors.New("invalid review capacity")
 )
 
 type UserRepository interface {
 	UserExists(ctx context.Context, userID string) (bool, error)
 	SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
-	GetReviews(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	GetReviews(ctx context.Context, userID string, includeClosed bool) ([]domain.PullRequest, error)
 	GetUserByID(ctx context.Context, userID string) (domain.User, error)
 	GetActiveTeamMembersExcept(ctx context.Context, teamName string, excludeID string) ([]domain.User, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	SetTags(ctx context.Context, userID string, tags []string) (domain.User, error)
+	AddTags(ctx context.Context, userID string, tags []string) (domain.User, error)
+	RemoveTags(ctx context.Context, userID string, tags []string) (domain.User, error)
+	CreateAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error)
+	ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error)
+	CancelAbsence(ctx context.Context, absenceID int64, cancelledAt time.Time) (domain.Absence, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (domain.User, error)
+	DeactivateUsers(
+		ctx context.Context,
+		target domain.DeactivationTarget,
+		plan func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error),
+	) ([]domain.User, error)
+	MoveUserToTeam(
+		ctx context.Context,
+		userID, teamName string,
+		plan func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error),
+	) (domain.User, error)
+	ListMemberships(ctx context.Context, userID string) ([]domain.TeamMembership, error)
 }
 
 type UserService struct {
 	URepository UserRepository
+	TRepository TeamRepository
 }
 
-func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
-	ok, err := s.URepository.UserExists(ctx, userID)
+// UserProfile is a user together with the teams they have been in,
+// oldest first.
+type UserProfile struct {
+	User        domain.User
+	Memberships []domain.TeamMembership
+}
+
+type ReviewLoad struct {
+	OpenReviews int
+	// Capacity is nil when the user has no limit.
+	Capacity *int
+}
+
+// SetIsActive activates or deactivates a user. Deactivation also hands
+// the user's open reviews over, see Deactivate.
+func (s *UserService) SetIsActive(
+	ctx context.Context,
+	userID string,
+	isActive bool,
+) (domain.User, []domain.ReviewHandover, error) {
+	if !isActive {
+		report, err := s.Deactivate(ctx, domain.DeactivationTarget{UserIDs: []string{userID}})
+		if err != nil {
+			return domain.User{}, nil, err
+		}
+		return report.Users[0], report.Handovers, nil
+	}
+
+	user, err := s.URepository.SetIsActive(ctx, userID, isActive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, nil, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	return user, nil, nil
+}
+
+func (s *UserService) getUser(ctx context.Context, userID string) (domain.User, error) {
+	user, err := s.URepository.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrUserNotFound
+	}
 	if err != nil {
 		return domain.User{}, err
 	}
+	return user, nil
+}
+
+func (s *UserService) GetReviews(ctx context.Context, userID string, includeClosed bool) ([]domain.PullRequest, error) {
+	ok, err := s.URepository.UserExists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return s.URepository.GetReviews(ctx, userID, includeClosed)
+}
+
+func (s *UserService) SetTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	return s.updateTags(ctx, userID, tags, s.URepository.SetTags)
+}
+
+func (s *UserService) AddTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	return s.updateTags(ctx, userID, tags, s.URepository.AddTags)
+}
+
+func (s *UserService) RemoveTags(ctx context.Context, userID string, tags []string) (domain.User, error) {
+	return s.updateTags(ctx, userID, tags, s.URepository.RemoveTags)
+}
+
+func (s *UserService) updateTags(
+	ctx context.Context,
+	userID string,
+	tags []string,
+	update func(ctx context.Context, userID string, tags []string) (domain.User, error),
+) (domain.User, error) {
+	user, err := update(ctx, userID, domain.NormalizeTags(tags))
+	if errors.Is(err, sql.ErrNoRows) {
 		return domain.User{}, ErrUserNotFound
 	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) CreateAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error) {
+	if !absence.EndsAt.After(absence.StartsAt) || !absence.EndsAt.After(time.Now()) {
+		return domain.Absence{}, ErrInvalidAbsence
+	}
+
+	ok, err := s.URepository.UserExists(ctx, absence.UserID)
+	if err != nil {
+		return domain.Absence{}, err
+	}
+	if !ok {
+		return domain.Absence{}, ErrUserNotFound
+	}
 
-	return s.URepository.SetIsActive(ctx, userID, isActive)
+	return s.URepository.CreateAbsence(ctx, absence)
 }
 
-func (s *UserService) GetReviews(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+func (s *UserService) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
 	ok, err := s.URepository.UserExists(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -43,5 +163,131 @@ func (s *UserService) GetReviews(ctx context.Context, userID string) ([]domain.P
 		return nil, ErrUserNotFound
 	}
 
-	return s.URepository.GetReviews(ctx, userID)
+	return s.URepository.ListAbsences(ctx, userID)
+}
+
+func (s *UserService) CancelAbsence(ctx context.Context, absenceID int64) (domain.Absence, error) {
+	absence, err := s.URepository.CancelAbsence(ctx, absenceID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Absence{}, ErrAbsenceNotFound
+	}
+	if err != nil {
+		return domain.Absence{}, err
+	}
+
+	return absence, nil
+}
+
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (domain.User, error) {
+	if maxOpenReviews != nil && *maxOpenReviews <= 0 {
+		return domain.User{}, ErrInvalidCapacity
+	}
+
+	user, err := s.URepository.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) GetReviewLoad(ctx context.Context, userID string) (ReviewLoad, error) {
+	user, err := s.URepository.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ReviewLoad{}, ErrUserNotFound
+	}
+	if err != nil {
+		return ReviewLoad{}, err
+	}
+
+	settings, err := s.TRepository.GetSettings(ctx, user.TeamName)
+	if err != nil {
+		return ReviewLoad{}, err
+	}
+
+	loads, err := s.URepository.CountOpenReviews(ctx, []string{userID})
+	if err != nil {
+		return ReviewLoad{}, err
+	}
+
+	return ReviewLoad{
+		OpenReviews: loads[userID],
+		Capacity:    user.Capacity(settings.MaxOpenReviews),
+	}, nil
+}
+
+// MoveTeam moves a user into another team and records the move in their
+// membership history. With reassignReviews, the open reviews the user holds
+// on pull requests of the old team's authors are handed over to the old
+// team like on deactivation; other reviews stay with the user.
+func (s *UserService) MoveTeam(
+	ctx context.Context,
+	userID, teamName string,
+	reassignReviews bool,
+) (domain.User, []domain.ReviewHandover, error) {
+	if userID == "" || teamName == "" {
+		return domain.User{}, nil, ErrInvalidMember
+	}
+
+	ok, err := s.TRepository.TeamExists(ctx, teamName)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	if !ok {
+		return domain.User{}, nil, ErrTeamNotFound
+	}
+
+	var before domain.User
+	var handovers []domain.ReviewHandover
+	user, err := s.URepository.MoveUserToTeam(
+		ctx,
+		userID,
+		teamName,
+		func(ctx context.Context, snapshot domain.DeactivationSnapshot) ([]domain.ReviewHandover, error) {
+			before = snapshot.Users[0]
+			if before.TeamName == teamName {
+				return nil, ErrMemberExists
+			}
+			if !reassignReviews || before.TeamName == "" {
+				return nil, nil
+			}
+
+			slots := snapshot.Slots[:0:0]
+			for _, slot := range snapshot.Slots {
+				if slot.AuthorTeam == before.TeamName {
+					slots = append(slots, slot)
+				}
+			}
+			snapshot.Slots = slots
+
+			var err error
+			handovers, err = s.planHandovers(ctx, snapshot, false, time.Now())
+			return handovers, err
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, nil, ErrUserNotFound
+	}
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	return user, handovers, nil
+}
+
+func (s *UserService) GetProfile(ctx context.Context, userID string) (UserProfile, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	memberships, err := s.URepository.ListMemberships(ctx, userID)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	return UserProfile{User: user, Memberships: memberships}, nil
 }
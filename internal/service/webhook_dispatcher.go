@@ -0,0 +1,161 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWebhookBatchSize   = 50
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookBackoff     = 30 * time.Second
+	maxWebhookBackoff         = time.Hour
+	webhookRequestTimeout     = 10 * time.Second
+	// webhookLease keeps a claimed delivery away from other dispatchers
+	// while it is being sent; it must outlast webhookRequestTimeout.
+	webhookLease = time.Minute
+)
+
+// WebhookDispatcher periodically sends due webhook deliveries. A failed
+// attempt is retried with exponential backoff until MaxAttempts is
+// reached, after which the delivery is marked FAILED. Deliveries are
+// claimed with a lease, so every replica may run a dispatcher.
+type WebhookDispatcher struct {
+	Repository WebhookRepository
+	Client     *http.Client
+	Interval   time.Duration
+	BatchSize  int
+	// MaxAttempts and Backoff default to 8 attempts starting 30s apart.
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("webhooks: dispatch failed: %v", err)
+			}
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries concurrently.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) error {
+	batch := d.BatchSize
+	if batch <= 0 {
+		batch = defaultWebhookBatchSize
+	}
+
+	deliveries, err := d.Repository.ClaimDueDeliveries(ctx, time.Now(), webhookLease, batch)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := d.Repository.RecordAttempt(ctx, d.attempt(ctx, delivery))
+			if err != nil {
+				log.Printf("webhooks: failed to record attempt of delivery %d: %v", delivery.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// attempt sends the delivery once and returns it updated with the outcome.
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = nil
+	delivery.LastError = ""
+
+	code, err := d.send(ctx, delivery)
+	if code != 0 {
+		delivery.LastStatusCode = &code
+	}
+	if err == nil {
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts() {
+		delivery.Status = domain.DeliveryFailed
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	return delivery
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery domain.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PR_project-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, SignPayload(delivery.Secret, delivery.Payload))
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) maxAttempts() int {
+	if d.MaxAttempts <= 0 {
+		return defaultWebhookMaxAttempts
+	}
+	return d.MaxAttempts
+}
+
+// backoff doubles the wait after every failed attempt, up to maxWebhookBackoff.
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.Backoff
+	if wait <= 0 {
+		wait = defaultWebhookBackoff
+	}
+	for i := 1; i < attempts && wait < maxWebhookBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxWebhookBackoff)
+}
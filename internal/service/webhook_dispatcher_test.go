@@ -0,0 +1,236 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSignPayload(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	want := "sha256=88267f36f7e6cafeb097afd76335e0a3a74ea79e2bc2e62989462bf0c75cfd0f"
+	if got := SignPayload("s3cret", body); got != want {
+		t.Errorf("SignPayload() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      string
+		signature string
+		want      bool
+	}{
+		{"valid", "s3cret", `{"id":"e1"}`, want, true},
+		{"other secret", "secret", `{"id":"e1"}`, want, false},
+		{"tampered body", "s3cret", `{"id":"e2"}`, want, false},
+		{"missing prefix", "s3cret", `{"id":"e1"}`, want[len("sha256="):], false},
+		{"empty", "s3cret", `{"id":"e1"}`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, []byte(tt.body), tt.signature); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// webhookReceiver answers with the queued status codes in turn, then 200,
+// and remembers every request it got.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.headers = append(rcv.headers, r.Header.Clone())
+	rcv.bodies = append(rcv.bodies, body)
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// fakeWebhookRepository hands out the queued deliveries once and keeps
+// the recorded attempts.
+type fakeWebhookRepository struct {
+	WebhookRepository
+	mu       sync.Mutex
+	due      []domain.WebhookDelivery
+	recorded []domain.WebhookDelivery
+}
+
+func (f *fakeWebhookRepository) ClaimDueDeliveries(context.Context, time.Time, time.Duration, int) ([]domain.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeWebhookRepository) RecordAttempt(_ context.Context, d domain.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorded = append(f.recorded, d)
+	return nil
+}
+
+func newDelivery(url string) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:        7,
+		EventType: domain.EventPrMerged,
+		Payload:   []byte(`{"id":"e1","type":"pull_request.merged"}`),
+		Status:    domain.DeliveryPending,
+		URL:       url,
+		Secret:    "s3cret",
+	}
+}
+
+func TestWebhookDispatcherSendsSignedRequest(t *testing.T) {
+	rcv := &webhookReceiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	d := &WebhookDispatcher{Client: srv.Client()}
+	delivery := newDelivery(srv.URL)
+	got := d.attempt(context.Background(), delivery)
+
+	if got.Status != domain.DeliveryDelivered || got.Attempts != 1 || got.DeliveredAt == nil {
+		t.Fatalf("attempt() = %+v, want delivered on the first attempt", got)
+	}
+	if got.LastStatusCode == nil || *got.LastStatusCode != http.StatusOK || got.LastError != "" {
+		t.Errorf("attempt() recorded status %v, error %q", got.LastStatusCode, got.LastError)
+	}
+
+	if len(rcv.headers) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(rcv.headers))
+	}
+	h := rcv.headers[0]
+	if !VerifySignature("s3cret", rcv.bodies[0], h.Get(SignatureHeader)) {
+		t.Errorf("%s = %q does not sign the body", SignatureHeader, h.Get(SignatureHeader))
+	}
+	if string(rcv.bodies[0]) != string(delivery.Payload) {
+		t.Errorf("body = %s, want the stored payload", rcv.bodies[0])
+	}
+	for name, want := range map[string]string{
+		"Content-Type":       "application/json",
+		"X-Webhook-Event":    domain.EventPrMerged,
+		"X-Webhook-Delivery": "7",
+	} {
+		if got := h.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWebhookDispatcherRetriesAfterServerError(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	repo := &fakeWebhookRepository{due: []domain.WebhookDelivery{newDelivery(srv.URL)}}
+	d := &WebhookDispatcher{Repository: repo, Client: srv.Client(), Backoff: time.Minute}
+
+	start := time.Now()
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if len(repo.recorded) != 1 {
+		t.Fatalf("recorded %d attempts, want 1", len(repo.recorded))
+	}
+	failed := repo.recorded[0]
+	if failed.Status != domain.DeliveryPending || failed.Attempts != 1 {
+		t.Fatalf("after a 503 delivery is %s with %d attempts, want PENDING with 1", failed.Status, failed.Attempts)
+	}
+	if failed.LastStatusCode == nil || *failed.LastStatusCode != http.StatusServiceUnavailable || failed.LastError == "" {
+		t.Errorf("after a 503 recorded status %v, error %q", failed.LastStatusCode, failed.LastError)
+	}
+	if wait := failed.NextAttemptAt.Sub(start); wait < time.Minute || wait > time.Minute+5*time.Second {
+		t.Errorf("next attempt in %s, want about the initial backoff", wait)
+	}
+
+	repo.due = []domain.WebhookDelivery{failed}
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	retried := repo.recorded[1]
+	if retried.Status != domain.DeliveryDelivered || retried.Attempts != 2 || retried.LastError != "" {
+		t.Errorf("retry = %+v, want delivered on the second attempt", retried)
+	}
+	if len(rcv.bodies) != 2 || string(rcv.bodies[0]) != string(rcv.bodies[1]) {
+		t.Errorf("receiver got %d requests, want the same body twice", len(rcv.bodies))
+	}
+}
+
+func TestWebhookDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d := &WebhookDispatcher{Client: srv.Client(), MaxAttempts: 3}
+	delivery := newDelivery(srv.URL)
+	for attempt := 1; attempt <= 3; attempt++ {
+		delivery = d.attempt(context.Background(), delivery)
+		want := domain.DeliveryPending
+		if attempt == 3 {
+			want = domain.DeliveryFailed
+		}
+		if delivery.Status != want || delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: delivery is %s with %d attempts, want %s", attempt, delivery.Status, delivery.Attempts, want)
+		}
+	}
+	if delivery.DeliveredAt != nil {
+		t.Errorf("failed delivery has DeliveredAt set")
+	}
+}
+
+func TestWebhookDispatcherRecordsConnectionErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	d := &WebhookDispatcher{MaxAttempts: 1}
+	got := d.attempt(context.Background(), newDelivery(url))
+	if got.Status != domain.DeliveryFailed || got.LastStatusCode != nil || got.LastError == "" {
+		t.Errorf("attempt() = status %s, code %v, error %q; want FAILED without a code", got.Status, got.LastStatusCode, got.LastError)
+	}
+}
+
+func TestWebhookDispatcherBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{"first retry waits the initial backoff", 0, 1, 30 * time.Second},
+		{"doubles after every attempt", 0, 2, time.Minute},
+		{"keeps doubling", 0, 4, 4 * time.Minute},
+		{"capped at an hour", 0, 8, time.Hour},
+		{"stays capped", 0, 40, time.Hour},
+		{"custom initial backoff", time.Second, 3, 4 * time.Second},
+		{"custom backoff is capped", 45 * time.Minute, 2, time.Hour},
+		{"initial backoff above the cap", 2 * time.Hour, 1, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &WebhookDispatcher{Backoff: tt.backoff}
+			if got := d.backoff(tt.attempts); got != tt.want {
+				t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
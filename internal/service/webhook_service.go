@@ -0,0 +1,167 @@
+package service
+
+import (
+	"PR_project/internal/domain"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWebhook       = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidDeliveryQuery = errors.New("invalid webhook delivery filter")
+)
+
+const (
+	defaultDeliveryPageSize = 50
+	maxDeliveryPageSize     = 500
+)
+
+// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the
+// request body, keyed with the subscription secret.
+const SignatureHeader = "X-Webhook-Signature"
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int64) error
+	EnqueueDeliveries(ctx context.Context, event domain.Event, payload []byte) (int, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, d domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, filter domain.DeliveryFilter) ([]domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int64) (domain.WebhookDelivery, error)
+}
+
+// WebhookService manages subscriptions and queues a delivery per
+// interested subscription for every published event. The deliveries
+// are sent by WebhookDispatcher.
+type WebhookService struct {
+	WRepository WebhookRepository
+}
+
+// webhookEnvelope is the body POSTed to subscribers.
+type webhookEnvelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func (s *WebhookService) Publish(ctx context.Context, event domain.Event) error {
+	payload, err := json.Marshal(webhookEnvelope{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.WRepository.EnqueueDeliveries(ctx, event, payload)
+	return err
+}
+
+// Subscribe registers a URL for the given event types, or for every
+// event when none are given. A secret is generated when none is set.
+func (s *WebhookService) Subscribe(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.WebhookSubscription{}, ErrInvalidWebhook
+	}
+
+	eventTypes := make([]string, 0, len(sub.EventTypes))
+	for _, t := range sub.EventTypes {
+		t = strings.TrimSpace(t)
+		if !domain.ValidEventType(t) {
+			return domain.WebhookSubscription{}, ErrInvalidWebhook
+		}
+		eventTypes = append(eventTypes, t)
+	}
+	sub.EventTypes = eventTypes
+
+	if sub.Secret == "" {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		sub.Secret = hex.EncodeToString(b)
+	}
+
+	return s.WRepository.CreateSubscription(ctx, sub)
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.WRepository.ListSubscriptions(ctx)
+}
+
+func (s *WebhookService) Unsubscribe(ctx context.Context, subscriptionID int64) error {
+	err := s.WRepository.DeleteSubscription(ctx, subscriptionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListDeliveries returns one page of the delivery log, newest first, and
+// the cursor for the next page, which is 0 when there are no more.
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	filter domain.DeliveryFilter,
+) ([]domain.WebhookDelivery, int64, error) {
+	if filter.Limit < 0 || filter.Limit > maxDeliveryPageSize || filter.BeforeID < 0 || filter.SubscriptionID < 0 {
+		return nil, 0, ErrInvalidDeliveryQuery
+	}
+	switch filter.Status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryFailed:
+	default:
+		return nil, 0, ErrInvalidDeliveryQuery
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDeliveryPageSize
+	}
+
+	deliveries, err := s.WRepository.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var next int64
+	if len(deliveries) == filter.Limit {
+		next = deliveries[len(deliveries)-1].ID
+	}
+	return deliveries, next, nil
+}
+
+// Redeliver queues the payload of an earlier delivery again as a new
+// delivery, whatever the outcome of the original.
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID int64) (domain.WebhookDelivery, error) {
+	d, err := s.WRepository.Redeliver(ctx, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	return d, err
+}
+
+// SignPayload returns the SignatureHeader value for body.
+func SignPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is the SignatureHeader value
+// for body, comparing in constant time.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignPayload(secret, body)), []byte(signature))
+}
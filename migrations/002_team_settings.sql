@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name     TEXT PRIMARY KEY,
+    min_reviewers INT NOT NULL DEFAULT 1,
+    max_reviewers INT NOT NULL DEFAULT 2,
+
+    CONSTRAINT fk_settings_team
+        FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_settings_reviewers
+        CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name          TEXT NOT NULL,
+    fallback_team_name TEXT NOT NULL,
+    priority           INT  NOT NULL,
+
+    PRIMARY KEY (team_name, fallback_team_name),
+
+    CONSTRAINT fk_fallback_team
+        FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_fallback_target
+        FOREIGN KEY (fallback_team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_fallback_not_self
+        CHECK (team_name <> fallback_team_name)
+);
@@ -0,0 +1,16 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS changed_files TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS ownership_rules (
+    team_name TEXT   NOT NULL,
+    position  INT    NOT NULL,
+    pattern   TEXT   NOT NULL,
+    owners    TEXT[] NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY (team_name, position),
+
+    CONSTRAINT fk_ownership_team
+        FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE
+);
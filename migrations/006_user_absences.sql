@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS user_absences (
+    absence_id   BIGSERIAL PRIMARY KEY,
+    user_id      TEXT        NOT NULL,
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL,
+    reason       TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    cancelled_at TIMESTAMPTZ,
+
+    CONSTRAINT fk_absence_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_absence_period
+        CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_absences_active
+    ON user_absences (user_id, starts_at, ends_at)
+    WHERE cancelled_at IS NULL;
@@ -0,0 +1,10 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS max_open_reviews INT
+        CONSTRAINT chk_settings_max_open_reviews CHECK (max_open_reviews > 0),
+    ADD COLUMN IF NOT EXISTS capacity_policy TEXT NOT NULL DEFAULT 'ASSIGN_FEWER'
+        CONSTRAINT chk_settings_capacity_policy
+        CHECK (capacity_policy IN ('ASSIGN_ANYWAY', 'ASSIGN_FEWER', 'REJECT'));
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS max_open_reviews INT
+        CONSTRAINT chk_users_max_open_reviews CHECK (max_open_reviews > 0);
@@ -0,0 +1,25 @@
+-- Rationale rows outlive the assignment they explain: a replaced reviewer
+-- keeps its row, stamped with replaced_at, so the table is keyed by its own
+-- id and cascades with the pull request rather than with pr_reviewers.
+CREATE TABLE IF NOT EXISTS pr_reviewer_rationale (
+    rationale_id     BIGSERIAL   PRIMARY KEY,
+    pull_request_id  TEXT        NOT NULL,
+    user_id          TEXT        NOT NULL,
+    strategy         TEXT        NOT NULL,
+    reason           TEXT        NOT NULL,
+    fallback_team    TEXT,
+    candidate_pool   TEXT[]      NOT NULL DEFAULT '{}',
+    replaced_user_id TEXT,
+    assigned_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    replaced_at      TIMESTAMPTZ,
+
+    CONSTRAINT fk_rationale_pr
+        FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id)
+        ON DELETE CASCADE
+);
+
+-- Each current reviewer has at most one current rationale.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pr_reviewer_rationale_current
+    ON pr_reviewer_rationale (pull_request_id, user_id)
+    WHERE replaced_at IS NULL;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS pr_reviews (
+    review_id       BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL,
+    user_id         TEXT        NOT NULL,
+    verdict         TEXT        NOT NULL,
+    comment         TEXT        NOT NULL DEFAULT '',
+    submitted_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_review_pr
+        FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_review_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_review_verdict
+        CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviews_latest
+    ON pr_reviews (pull_request_id, user_id, submitted_at DESC);
+
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS approval_policy TEXT NOT NULL DEFAULT 'NONE'
+        CONSTRAINT chk_settings_approval_policy
+        CHECK (approval_policy IN ('NONE', 'ALL_APPROVED', 'MIN_APPROVALS')),
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 1
+        CONSTRAINT chk_settings_required_approvals CHECK (required_approvals > 0);
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+
+ALTER TABLE pull_requests
+    ADD CONSTRAINT chk_pr_status CHECK (status IN ('OPEN', 'MERGED', 'CLOSED'));
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS chk_pr_status;
+
+ALTER TABLE pull_requests
+    ADD CONSTRAINT chk_pr_status CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
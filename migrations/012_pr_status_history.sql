@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS pr_status_history (
+    history_id      BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL,
+    from_status     TEXT,
+    to_status       TEXT        NOT NULL,
+    actor           TEXT,
+    reason          TEXT        NOT NULL DEFAULT '',
+    changed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_status_history_pr
+        FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_status_history_pr
+    ON pr_status_history (pull_request_id, changed_at, history_id);
+
+-- Pull requests created before the history existed get a single entry
+-- for their current status.
+INSERT INTO pr_status_history (pull_request_id, from_status, to_status, reason, changed_at)
+SELECT pull_request_id, NULL, status, 'backfilled', COALESCE(merged_at, closed_at, created_at)
+FROM pull_requests
+WHERE NOT EXISTS (
+    SELECT 1 FROM pr_status_history h WHERE h.pull_request_id = pull_requests.pull_request_id
+);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id    BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    actor       TEXT,
+    action      TEXT        NOT NULL,
+    entity_type TEXT        NOT NULL,
+    entity_id   TEXT        NOT NULL,
+    before      JSONB,
+    after       JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity
+    ON audit_log (entity_type, entity_id, audit_id);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor
+    ON audit_log (actor, audit_id);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at
+    ON audit_log (occurred_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_no_update ON audit_log;
+CREATE TRIGGER trg_audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS trg_audit_log_no_truncate ON audit_log;
+CREATE TRIGGER trg_audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,27 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS review_sla_minutes INT
+        CONSTRAINT chk_settings_review_sla CHECK (review_sla_minutes > 0),
+    ADD COLUMN IF NOT EXISTS sla_action TEXT NOT NULL DEFAULT 'ESCALATE'
+        CONSTRAINT chk_settings_sla_action CHECK (sla_action IN ('ESCALATE', 'REASSIGN'));
+
+CREATE TABLE IF NOT EXISTS pr_escalations (
+    escalation_id   BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL,
+    user_id         TEXT        NOT NULL,
+    action          TEXT        NOT NULL,
+    replaced_by     TEXT,
+    note            TEXT        NOT NULL DEFAULT '',
+    sla_started_at  TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    -- One escalation per reviewer assignment, even with several workers.
+    CONSTRAINT uq_escalation_assignment UNIQUE (pull_request_id, user_id, sla_started_at),
+
+    CONSTRAINT fk_escalation_pr
+        FOREIGN KEY (pull_request_id)
+        REFERENCES pull_requests(pull_request_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_escalation_action
+        CHECK (action IN ('ESCALATED', 'REASSIGNED'))
+);
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    subscription_id BIGSERIAL PRIMARY KEY,
+    url             TEXT        NOT NULL,
+    secret          TEXT        NOT NULL,
+    event_types     TEXT[]      NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id      BIGSERIAL PRIMARY KEY,
+    subscription_id  BIGINT      NOT NULL,
+    event_id         TEXT        NOT NULL,
+    event_type       TEXT        NOT NULL,
+    payload          BYTEA       NOT NULL,
+    status           TEXT        NOT NULL DEFAULT 'PENDING',
+    attempts         INT         NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at  TIMESTAMPTZ,
+    last_status_code INT,
+    last_error       TEXT        NOT NULL DEFAULT '',
+    delivered_at     TIMESTAMPTZ,
+    redelivery_of    BIGINT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_delivery_subscription
+        FOREIGN KEY (subscription_id)
+        REFERENCES webhook_subscriptions(subscription_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_delivery_redelivery_of
+        FOREIGN KEY (redelivery_of)
+        REFERENCES webhook_deliveries(delivery_id)
+        ON DELETE SET NULL,
+
+    CONSTRAINT chk_delivery_status
+        CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON webhook_deliveries (subscription_id, delivery_id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    event_id        TEXT PRIMARY KEY,
+    event_type      TEXT        NOT NULL,
+    actor           TEXT,
+    data            JSONB       NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished
+    ON outbox (next_attempt_at)
+    WHERE published_at IS NULL;
+
+-- Outbox events are delivered at least once; an event published twice
+-- must not queue a second webhook delivery. Redeliveries are exempt.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_delivery_event
+    ON webhook_deliveries (subscription_id, event_id)
+    WHERE redelivery_of IS NULL;
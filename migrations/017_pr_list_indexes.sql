@@ -0,0 +1,26 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Default listing order and cursor seeks.
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created
+    ON pull_requests (created_at DESC, pull_request_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created
+    ON pull_requests (author_id, created_at DESC, pull_request_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created
+    ON pull_requests (status, created_at DESC, pull_request_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged
+    ON pull_requests (merged_at)
+    WHERE merged_at IS NOT NULL;
+
+-- Substring search on the name.
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm
+    ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
+
+-- The primary key leads with pull_request_id; lookups by reviewer need their own.
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user
+    ON pr_reviewers (user_id, pull_request_id);
+
+CREATE INDEX IF NOT EXISTS idx_users_team
+    ON users (team_name);
@@ -0,0 +1,4 @@
+-- A member removed from their team keeps their pull requests, reviews and
+-- audit trail; they simply belong to no team until added to one again.
+ALTER TABLE users
+    ALTER COLUMN team_name DROP NOT NULL;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS team_memberships (
+    membership_id BIGSERIAL PRIMARY KEY,
+    user_id       TEXT        NOT NULL,
+    team_name     TEXT        NOT NULL,
+    joined_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    left_at       TIMESTAMPTZ,
+    reason        TEXT        NOT NULL DEFAULT '',
+    actor         TEXT,
+
+    CONSTRAINT fk_membership_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_membership_team
+        FOREIGN KEY (team_name)
+        REFERENCES teams(team_name)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_membership_period
+        CHECK (left_at IS NULL OR left_at >= joined_at)
+);
+
+-- A user is in at most one team at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_team_memberships_current
+    ON team_memberships (user_id)
+    WHERE left_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_team_memberships_user
+    ON team_memberships (user_id, joined_at, membership_id);
+
+-- Users who joined before the history existed get an open membership
+-- in their current team.
+INSERT INTO team_memberships (user_id, team_name, reason)
+SELECT user_id, team_name, 'backfilled'
+FROM users
+WHERE team_name IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM team_memberships m WHERE m.user_id = users.user_id
+  );